@@ -11,7 +11,10 @@ import (
 	"github.com/ebauman/moo/pkg/rpc"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"io/ioutil"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"os"
+	"strings"
 )
 
 var logger *log.Logger
@@ -94,6 +97,51 @@ func main() {
 				Usage: "path to file containing ca certificate(s) for the moo server (PEM format)",
 				EnvVars: []string{"MOO_SERVER_CACERTS"},
 			},
+			&cli.StringFlag{
+				Name: "moo-client-cert",
+				Usage: "path to client certificate presented to the moo server (PEM format)",
+				EnvVars: []string{"MOO_CLIENT_CERT"},
+			},
+			&cli.StringFlag{
+				Name: "moo-client-key",
+				Usage: "path to key for the moo client certificate (PEM format)",
+				EnvVars: []string{"MOO_CLIENT_KEY"},
+			},
+			&cli.StringFlag{
+				Name: "moo-secret",
+				Usage: "shared secret presented to the moo server at registration",
+				EnvVars: []string{"MOO_SECRET"},
+			},
+			&cli.StringFlag{
+				Name: "moo-secret-file",
+				Usage: "path to file containing the shared secret",
+				EnvVars: []string{"MOO_SECRET_FILE"},
+			},
+			&cli.StringFlag{
+				Name: "moo-secret-ref",
+				Usage: "kubernetes secret containing the shared secret, as namespace/name/key",
+				EnvVars: []string{"MOO_SECRET_REF"},
+			},
+			&cli.StringSliceFlag{
+				Name: "moo-label",
+				Usage: "label reported to the moo server, as key=value",
+				EnvVars: []string{"MOO_LABELS"},
+			},
+			&cli.BoolFlag{
+				Name: "moo-no-auto-labels",
+				Usage: "don't report labels collected from the cluster (kubernetes version, provider, node labels)",
+				EnvVars: []string{"MOO_NO_AUTO_LABELS"},
+			},
+			&cli.BoolFlag{
+				Name: "moo-retry-denied",
+				Usage: "keep checking in with the moo server when denied instead of exiting",
+				EnvVars: []string{"MOO_RETRY_DENIED"},
+			},
+			&cli.StringFlag{
+				Name: "moo-token",
+				Usage: "agent token for the moo server",
+				EnvVars: []string{"MOO_TOKEN"},
+			},
 			&cli.StringFlag {
 				Name: "loglevel",
 				Usage: "log level (trace, debug, info, warning, error, fatal, panic)",
@@ -144,11 +192,82 @@ func buildConfigFromFlags(ctx *cli.Context) *config.AgentConfig {
 	cfg.Insecure = ctx.Bool("rancher-insecure")
 	cfg.UseExisting = ctx.Bool("use-existing-cluster")
 	cfg.ServerHostname = ctx.String("moo-server")
+	cfg.ServerInsecure = ctx.Bool("moo-insecure")
 	cfg.CACerts = ctx.String("moo-cacerts")
+	cfg.ClientCert = ctx.String("moo-client-cert")
+	cfg.ClientKey = ctx.String("moo-client-key")
+	cfg.Token = ctx.String("moo-token")
+	cfg.Secret = ctx.String("moo-secret")
+	cfg.RetryDenied = ctx.Bool("moo-retry-denied")
+	cfg.NoAutoLabels = ctx.Bool("moo-no-auto-labels")
+	cfg.SecretFile = ctx.String("moo-secret-file")
+	cfg.SecretRef = ctx.String("moo-secret-ref")
 
 	return cfg
 }
 
+// loadSecret resolves the shared secret from the first of --moo-secret,
+// --moo-secret-file or --moo-secret-ref that is set
+func loadSecret(cfg *config.AgentConfig, k8sClient *kubernetes.KubernetesClient) (string, error) {
+	if cfg.Secret != "" {
+		return cfg.Secret, nil
+	}
+
+	if cfg.SecretFile != "" {
+		secret, err := ioutil.ReadFile(cfg.SecretFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	if cfg.SecretRef != "" {
+		ref := strings.Split(cfg.SecretRef, "/")
+		if len(ref) != 3 {
+			return "", fmt.Errorf("invalid secret reference %s, expected namespace/name/key", cfg.SecretRef)
+		}
+		secret, err := k8sClient.GetSecretValue(ref[0], ref[1], ref[2])
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	return "", nil
+}
+
+// loadLabels collects labels from the cluster, unless disabled, and adds those
+// given with --moo-label, which take precedence
+func loadLabels(ctx *cli.Context, cfg *config.AgentConfig, k8sClient *kubernetes.KubernetesClient) (map[string]string, error) {
+	labels := map[string]string{}
+
+	if !cfg.NoAutoLabels {
+		collected, err := k8sClient.CollectLabels()
+		if err != nil {
+			getLogger(ctx).Warnf("error collecting labels from the cluster: %v", err)
+		}
+		for k, v := range collected {
+			labels[k] = v
+		}
+	}
+
+	for _, l := range ctx.StringSlice("moo-label") {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid label %s, expected key=value", l)
+		}
+		if errs := validation.IsQualifiedName(kv[0]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %s: %s", kv[0], strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(kv[1]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value for label %s: %s", kv[0], strings.Join(errs, "; "))
+		}
+		labels[kv[0]] = kv[1]
+	}
+
+	return labels, nil
+}
+
 func run(ctx *cli.Context) error {
 	// using the ctx, build a context and related items.
 
@@ -167,7 +286,24 @@ func run(ctx *cli.Context) error {
 
 	var ag *agent.Agent
 	if cfg.ServerHostname != "" {
-		mooClient, err := rpc.SetupMooClient(cfg.ServerHostname, cfg.Insecure, cfg.CACerts)
+		secret, err := loadSecret(cfg, k8sClient)
+		if err != nil {
+			logger.Fatalf("error loading shared secret: %v", err)
+		}
+		cfg.Secret = secret
+
+		if cfg.Labels, err = loadLabels(ctx, cfg, k8sClient); err != nil {
+			logger.Fatalf("error loading labels: %v", err)
+		}
+
+		mooClient, err := rpc.SetupMooClient(rpc.ClientOptions{
+			Hostname:   cfg.ServerHostname,
+			Insecure:   cfg.ServerInsecure,
+			CACerts:    cfg.CACerts,
+			ClientCert: cfg.ClientCert,
+			ClientKey:  cfg.ClientKey,
+			Token:      cfg.Token,
+		})
 		if err != nil {
 			logger.Fatalf("error building moo client: %v", err)
 		}
@@ -1,12 +1,16 @@
 package agent
 
 import (
+	"context"
 	"fmt"
+	"github.com/ebauman/moo/mooctl/cmd/client"
 	"github.com/ebauman/moo/pkg/rpc"
 	"github.com/liggitt/tabwriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -20,12 +24,50 @@ func LoadCommand() *cli.Command{
 				Usage: "list agents",
 				Action: listAgents,
 			},
+			{
+				Name: "requeue",
+				Usage: "send denied, held or errored agents back to pending so the rules are evaluated again",
+				ArgsUsage: "<id> [<id>...]",
+				Action: requeueAgents,
+				Flags: reasonFlags(),
+			},
+			{
+				Name: "approve",
+				Usage: "accept agents regardless of the rules",
+				ArgsUsage: "<id> [<id>...]",
+				Action: approveAgents,
+				Flags: append(reasonFlags(), &cli.BoolFlag{
+					Name:  "allow-adoption",
+					Usage: "let the agents adopt an existing rancher cluster that isn't bound to them",
+				}),
+			},
+			{
+				Name: "deny",
+				Usage: "deny agents regardless of the rules",
+				ArgsUsage: "<id> [<id>...]",
+				Action: denyAgents,
+				Flags: reasonFlags(),
+			},
+			{
+				Name: "hold",
+				Usage: "hold agents until they are approved, denied or requeued",
+				ArgsUsage: "<id> [<id>...]",
+				Action: holdAgents,
+				Flags: reasonFlags(),
+			},
+			{
+				Name: "delete",
+				Usage: "delete agents",
+				ArgsUsage: "<id> [<id>...]",
+				Action: deleteAgents,
+				Flags: reasonFlags(),
+			},
 		},
 	}
 }
 
 func listAgents(c *cli.Context) error {
-	mooClient, _, err := rpc.SetupClients(c.String("server"), c.Bool("insecure"), c.String("cacerts"))
+	mooClient, _, err := client.Setup(c)
 	if err != nil {
 		return err
 	}
@@ -44,6 +86,8 @@ func listAgents(c *cli.Context) error {
 		agentStatus = rpc.Status_Pending
 	case "error":
 		agentStatus = rpc.Status_Error
+	case "registered":
+		agentStatus = rpc.Status_Registered
 	default:
 		log.Fatalf("invalid agent status type %s specified", c.String("status"))
 	}
@@ -58,17 +102,128 @@ func listAgents(c *cli.Context) error {
 	return nil
 }
 
+func reasonFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "reason recorded in the agent's status message",
+		},
+	}
+}
+
+func requeueAgents(c *cli.Context) error {
+	mooClient, _, err := client.Setup(c)
+	if err != nil {
+		return err
+	}
+
+	return actOnAgents(c, "RequeueAgent", "requeued", mooClient.RequeueAgent)
+}
+
+func approveAgents(c *cli.Context) error {
+	mooClient, _, err := client.Setup(c)
+	if err != nil {
+		return err
+	}
+
+	allowAdoption := c.Bool("allow-adoption")
+	return actOnAgents(c, "ApproveAgent", "approved", func(ctx context.Context, action *rpc.AgentAction, opts ...grpc.CallOption) (*rpc.Agent, error) {
+		action.AllowAdoption = allowAdoption
+		return mooClient.ApproveAgent(ctx, action, opts...)
+	})
+}
+
+func denyAgents(c *cli.Context) error {
+	mooClient, _, err := client.Setup(c)
+	if err != nil {
+		return err
+	}
+
+	return actOnAgents(c, "DenyAgent", "denied", mooClient.DenyAgent)
+}
+
+func holdAgents(c *cli.Context) error {
+	mooClient, _, err := client.Setup(c)
+	if err != nil {
+		return err
+	}
+
+	return actOnAgents(c, "HoldAgent", "held", mooClient.HoldAgent)
+}
+
+func deleteAgents(c *cli.Context) error {
+	mooClient, _, err := client.Setup(c)
+	if err != nil {
+		return err
+	}
+	if c.NArg() == 0 {
+		return fmt.Errorf("no agent id specified")
+	}
+
+	for _, id := range c.Args().Slice() {
+		if _, err := mooClient.DeleteAgent(c.Context, &rpc.AgentAction{ID: id, Reason: c.String("reason")}); err != nil {
+			log.Errorf("error while calling DeleteAgent for %s: %s", id, err)
+			continue
+		}
+		fmt.Printf("agent %s deleted\n", id)
+	}
+
+	return nil
+}
+
+// actOnAgents calls an admin action for each agent id given as an argument
+func actOnAgents(c *cli.Context, method string, verb string, call func(context.Context, *rpc.AgentAction, ...grpc.CallOption) (*rpc.Agent, error)) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("no agent id specified")
+	}
+
+	for _, id := range c.Args().Slice() {
+		agent, err := call(c.Context, &rpc.AgentAction{ID: id, Reason: c.String("reason")})
+		if err != nil {
+			log.Errorf("error while calling %s for %s: %s", method, id, err)
+			continue
+		}
+		fmt.Printf("agent %s %s\n", agent.ID, verb)
+	}
+
+	return nil
+}
+
 func printAgents(agents *rpc.AgentListResponse) {
 	tabwriter := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.RememberWidths)
 	defer tabwriter.Flush()
 
-	headers := []string{"ID", "CLUSTER NAME", "SECRET", "USE EXISTING", "IP", "STATUS", "STATUS MESSAGE"}
+	headers := []string{"ID", "CLUSTER NAME", "RANCHER CLUSTER", "USE EXISTING", "IP", "IDENTITY", "LABELS", "STATUS", "STATUS MESSAGE"}
 	_, err := fmt.Fprintf(tabwriter, "%s\n", strings.Join(headers, "\t"))
 	if err != nil {
 		log.Fatalf("failed to print headers")
 	}
 
 	for _, agent := range agents.Agents {
-		fmt.Fprintf(tabwriter,"%s\t%s\t%s\t%t\t%s\t%s\t%s\t\n", agent.ID, agent.ClusterName, "[hidden]", agent.UseExisting, agent.IP, agent.Status, agent.StatusMessage)
+		fmt.Fprintf(tabwriter,"%s\t%s\t%s\t%t\t%s\t%s\t%s\t%s\t%s\t\n", agent.ID, agent.ClusterName, describeRancherCluster(agent), agent.UseExisting, agent.IP, agent.Identity, describeLabels(agent.Labels), agent.Status, agent.StatusMessage)
+	}
+}
+
+// describeRancherCluster prints the rancher cluster of an agent as name (id)
+func describeRancherCluster(a *rpc.Agent) string {
+	if a.RancherClusterID == "" {
+		return ""
 	}
-}
\ No newline at end of file
+
+	return fmt.Sprintf("%s (%s)", a.RancherClusterName, a.RancherClusterID)
+}
+
+// describeLabels prints labels sorted by key, as key=value,key=value
+func describeLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		keys[i] = k + "=" + labels[k]
+	}
+
+	return strings.Join(keys, ",")
+}
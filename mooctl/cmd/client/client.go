@@ -0,0 +1,30 @@
+package client
+
+import (
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+func options(c *cli.Context) rpc.ClientOptions {
+	return rpc.ClientOptions{
+		Hostname:   c.String("server"),
+		Insecure:   c.Bool("insecure"),
+		CACerts:    c.String("cacerts"),
+		ClientCert: c.String("client-cert"),
+		ClientKey:  c.String("client-key"),
+		Token:      c.String("token"),
+	}
+}
+
+// Setup builds moo server clients from mooctl's global flags
+func Setup(c *cli.Context) (rpc.MooClient, rpc.RulesClient, error) {
+	return rpc.SetupClients(options(c))
+}
+
+func SetupTokens(c *cli.Context) (rpc.TokensClient, error) {
+	return rpc.SetupTokensClient(options(c))
+}
+
+func SetupSecrets(c *cli.Context) (rpc.SecretsClient, error) {
+	return rpc.SetupSecretsClient(options(c))
+}
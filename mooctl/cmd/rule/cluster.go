@@ -0,0 +1,224 @@
+package rule
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/urfave/cli/v2"
+	"sort"
+	"strings"
+)
+
+func clusterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "cluster-description",
+			Usage: "description of the rancher clusters created for agents an accept rule matches",
+		},
+		&cli.StringSliceFlag{
+			Name:  "cluster-label",
+			Usage: "label set on created rancher clusters, as key=value",
+		},
+		&cli.StringSliceFlag{
+			Name:  "cluster-annotation",
+			Usage: "annotation set on created rancher clusters, as key=value",
+		},
+		&cli.BoolFlag{
+			Name:  "cluster-alerting",
+			Usage: "enable cluster alerting on created rancher clusters",
+		},
+		&cli.BoolFlag{
+			Name:  "cluster-monitoring",
+			Usage: "enable cluster monitoring on created rancher clusters",
+		},
+		&cli.BoolFlag{
+			Name:  "cluster-network-policy",
+			Usage: "enable project network isolation on created rancher clusters",
+		},
+		&cli.StringSliceFlag{
+			Name:  "cluster-project",
+			Usage: "project created in created rancher clusters, as <name>[=<description>]",
+		},
+		&cli.StringSliceFlag{
+			Name:  "cluster-member",
+			Usage: "cluster role granted in created rancher clusters, as user:<id>=<role>, user-principal:<id>=<role> or group:<principal id>=<role>",
+		},
+		&cli.BoolFlag{
+			Name:  "clear-cluster-settings",
+			Usage: "remove the cluster settings from the rule",
+		},
+	}
+}
+
+// applyClusterFlags sets the cluster settings of a rule from the flags that
+// were given
+func applyClusterFlags(c *cli.Context, rule *rpc.Rule) error {
+	if c.Bool("clear-cluster-settings") {
+		rule.Cluster = nil
+	}
+
+	set := false
+	for _, f := range []string{"cluster-description", "cluster-label", "cluster-annotation", "cluster-alerting", "cluster-monitoring", "cluster-network-policy", "cluster-project", "cluster-member"} {
+		set = set || c.IsSet(f)
+	}
+	if !set {
+		return nil
+	}
+
+	if rule.Cluster == nil {
+		rule.Cluster = &rpc.ClusterSettings{}
+	}
+	settings := rule.Cluster
+
+	if c.IsSet("cluster-description") {
+		settings.Description = c.String("cluster-description")
+	}
+	if c.IsSet("cluster-label") {
+		labels, err := parseKeyValues(c.StringSlice("cluster-label"))
+		if err != nil {
+			return err
+		}
+		settings.Labels = labels
+	}
+	if c.IsSet("cluster-annotation") {
+		annotations, err := parseKeyValues(c.StringSlice("cluster-annotation"))
+		if err != nil {
+			return err
+		}
+		settings.Annotations = annotations
+	}
+	if c.IsSet("cluster-alerting") {
+		settings.EnableClusterAlerting = c.Bool("cluster-alerting")
+	}
+	if c.IsSet("cluster-monitoring") {
+		settings.EnableClusterMonitoring = c.Bool("cluster-monitoring")
+	}
+	if c.IsSet("cluster-network-policy") {
+		settings.EnableNetworkPolicy = c.Bool("cluster-network-policy")
+	}
+	if c.IsSet("cluster-project") {
+		settings.Projects = nil
+		for _, v := range c.StringSlice("cluster-project") {
+			kv := strings.SplitN(v, "=", 2)
+			project := &rpc.ClusterProject{Name: kv[0]}
+			if len(kv) == 2 {
+				project.Description = kv[1]
+			}
+			settings.Projects = append(settings.Projects, project)
+		}
+	}
+	if c.IsSet("cluster-member") {
+		settings.Members = nil
+		for _, v := range c.StringSlice("cluster-member") {
+			member, err := parseMember(v)
+			if err != nil {
+				return err
+			}
+			settings.Members = append(settings.Members, member)
+		}
+	}
+
+	return nil
+}
+
+// parseMember parses <kind>:<id>=<role>. principal ids may contain colons,
+// so the role is taken after the last = and the kind before the first colon.
+func parseMember(s string) (*rpc.ClusterMember, error) {
+	i := strings.LastIndex(s, "=")
+	j := strings.Index(s, ":")
+	if i < 0 || j < 0 || j > i {
+		return nil, fmt.Errorf("invalid member %s, expected <kind>:<id>=<role>", s)
+	}
+
+	member := &rpc.ClusterMember{Role: s[i+1:]}
+	id := s[j+1 : i]
+	switch s[:j] {
+	case "user":
+		member.UserID = id
+	case "user-principal":
+		member.UserPrincipalID = id
+	case "group":
+		member.GroupPrincipalID = id
+	default:
+		return nil, fmt.Errorf("invalid member kind %s, expected user, user-principal or group", s[:j])
+	}
+
+	return member, nil
+}
+
+func describeMember(m *rpc.ClusterMember) string {
+	switch {
+	case m.UserID != "":
+		return fmt.Sprintf("user:%s=%s", m.UserID, m.Role)
+	case m.UserPrincipalID != "":
+		return fmt.Sprintf("user-principal:%s=%s", m.UserPrincipalID, m.Role)
+	default:
+		return fmt.Sprintf("group:%s=%s", m.GroupPrincipalID, m.Role)
+	}
+}
+
+// parseKeyValues parses key=value pairs given on the command line
+func parseKeyValues(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	m := map[string]string{}
+	for _, v := range values {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid value %s, expected key=value", v)
+		}
+		m[kv[0]] = kv[1]
+	}
+
+	return m, nil
+}
+
+// describeCluster prints the cluster settings of a rule, one per line
+func describeCluster(settings *rpc.ClusterSettings) string {
+	if settings == nil {
+		return ""
+	}
+
+	var lines []string
+	if settings.Description != "" {
+		lines = append(lines, "description: "+settings.Description)
+	}
+	if len(settings.Labels) > 0 {
+		lines = append(lines, "labels: "+describeKeyValues(settings.Labels))
+	}
+	if len(settings.Annotations) > 0 {
+		lines = append(lines, "annotations: "+describeKeyValues(settings.Annotations))
+	}
+	for _, p := range settings.Projects {
+		line := "project: " + p.Name
+		if p.Description != "" {
+			line += " (" + p.Description + ")"
+		}
+		lines = append(lines, line)
+	}
+	for _, m := range settings.Members {
+		lines = append(lines, "member: "+describeMember(m))
+	}
+	lines = append(lines,
+		fmt.Sprintf("alerting: %t", settings.EnableClusterAlerting),
+		fmt.Sprintf("monitoring: %t", settings.EnableClusterMonitoring),
+		fmt.Sprintf("network policy: %t", settings.EnableNetworkPolicy),
+	)
+
+	return strings.Join(lines, "\n")
+}
+
+func describeKeyValues(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		keys[i] = k + "=" + m[k]
+	}
+
+	return strings.Join(keys, ",")
+}
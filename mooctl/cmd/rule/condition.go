@@ -0,0 +1,100 @@
+package rule
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/rpc"
+	"strings"
+)
+
+// parseCondition parses a compound rule condition from the command line. the
+// same syntax is used when printing rules.
+func parseCondition(s string) (*rpc.Condition, error) {
+	condition := &rpc.Condition{}
+
+	if strings.HasPrefix(s, "!") {
+		condition.Not = true
+		s = s[1:]
+	}
+
+	key, value := s, ""
+	if i := strings.Index(s, "="); i >= 0 {
+		key, value = s[:i], s[i+1:]
+	}
+
+	switch key {
+	case "all":
+		condition.Type = rpc.RuleType_All
+	case "cluster-name":
+		condition.Type = rpc.RuleType_ClusterName
+		condition.Regex = value
+	case "source-ip":
+		condition.Type = rpc.RuleType_SourceIP
+		condition.Regex = value
+	case "cidr":
+		condition.Type = rpc.RuleType_SourceIP
+		condition.CIDRs = splitList(value)
+	case "shared-secret":
+		condition.Type = rpc.RuleType_SharedSecret
+		condition.SecretIDs = splitList(value)
+	case "labels":
+		condition.Type = rpc.RuleType_Labels
+		condition.Selector = value
+	default:
+		return nil, fmt.Errorf("invalid condition %s", s)
+	}
+
+	return condition, nil
+}
+
+func describeCondition(c *rpc.Condition) string {
+	var s string
+	switch c.Type {
+	case rpc.RuleType_All:
+		s = "all"
+	case rpc.RuleType_ClusterName:
+		s = "cluster-name=" + c.Regex
+	case rpc.RuleType_SourceIP:
+		if len(c.CIDRs) > 0 {
+			s = "cidr=" + strings.Join(c.CIDRs, ",")
+		} else {
+			s = "source-ip=" + c.Regex
+		}
+	case rpc.RuleType_SharedSecret:
+		s = "shared-secret"
+		if len(c.SecretIDs) > 0 {
+			s += "=" + strings.Join(c.SecretIDs, ",")
+		}
+	case rpc.RuleType_Labels:
+		s = "labels=" + c.Selector
+	default:
+		s = c.Type.String()
+	}
+
+	if c.Not {
+		s = "!" + s
+	}
+
+	return s
+}
+
+// describeRule prints what a rule matches on in condition syntax
+func describeRule(r *rpc.Rule) string {
+	if r.Type != rpc.RuleType_Compound {
+		return describeCondition(&rpc.Condition{Type: r.Type, Regex: r.Regex, SecretIDs: r.SecretIDs, CIDRs: r.CIDRs, Selector: r.Selector})
+	}
+
+	conditions := make([]string, len(r.Conditions))
+	for i, c := range r.Conditions {
+		conditions[i] = describeCondition(c)
+	}
+
+	return strings.Join(conditions, " "+strings.ToLower(r.Operator.String())+" ")
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, ",")
+}
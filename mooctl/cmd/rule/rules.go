@@ -2,6 +2,7 @@ package rule
 
 import (
 	"fmt"
+	"github.com/ebauman/moo/mooctl/cmd/client"
 	"github.com/ebauman/moo/pkg/rpc"
 	"github.com/liggitt/tabwriter"
 	log "github.com/sirupsen/logrus"
@@ -17,81 +18,281 @@ func LoadCommand() *cli.Command {
 		Subcommands: []*cli.Command{
 			{
 				Name: "list",
-				Usage: "list rules",
+				Usage: "list rules in evaluation order",
 				Action: listRules,
 			},
+			{
+				Name: "get",
+				Usage: "get rule",
+				ArgsUsage: "<id>",
+				Action: getRule,
+			},
 			{
 				Name: "delete",
 				Usage: "delete rule",
+				ArgsUsage: "<id>",
 				Action: deleteRule,
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:  "resource-version",
+						Usage: "only delete the rule if it is still at this version",
+					},
+				},
 			},
 			{
-				Name: "create",
-				Usage: "create rule",
-				Action: createRule,
+				Name: "test",
+				Usage: "show which rules match an agent, without changing anything",
+				Action: testRules,
 				Flags: []cli.Flag{
 					&cli.StringFlag{
-						Name:     "type",
-						Usage:    "rule type (all, cluster-name, source-ip, shared-secret)",
-						Required: true,
+						Name:  "agent",
+						Usage: "id of an existing agent to evaluate, other flags override its fields",
 					},
 					&cli.StringFlag{
-						Name:     "action",
-						Usage:    "action (accept, hold, deny)",
-						Required: true,
+						Name:  "cluster-name",
+						Usage: "cluster name",
 					},
-					&cli.IntFlag{
-						Name:     "priority",
-						Usage:    "priority (rules sorted in descending order)",
-						Required: true,
+					&cli.StringFlag{
+						Name:  "ip",
+						Usage: "source ip",
 					},
 					&cli.StringFlag{
-						Name:  "regex",
-						Usage: "regex for rules that accept it (cluster-name, source-ip)",
+						Name:  "secret",
+						Usage: "shared secret",
+					},
+					&cli.StringSliceFlag{
+						Name:  "label",
+						Usage: "agent label, as key=value, replacing the agent's labels",
+					},
+					&cli.StringFlag{
+						Name:  "at",
+						Usage: "evaluate as of this time (RFC 3339) instead of now",
 					},
 				},
 			},
+			{
+				Name: "create",
+				Usage: "create rule",
+				Action: createRule,
+				Flags: ruleFlags(true),
+			},
+			{
+				Name: "update",
+				Usage: "update rule, changing only the given flags",
+				ArgsUsage: "<id>",
+				Action: updateRule,
+				Flags: append(ruleFlags(false),
+					&cli.Int64Flag{
+						Name:  "resource-version",
+						Usage: "version the update is based on (default the version read before updating)",
+					},
+				),
+			},
 		},
 	}
 }
 
-func createRule(c *cli.Context) error {
-	_, rulesClient, err := rpc.SetupClients(c.String("server"), c.Bool("insecure"), c.String("cacerts"))
-	if err != nil {
-		log.Fatal(err)
+func ruleFlags(required bool) []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "type",
+			Usage:    "rule type (all, cluster-name, source-ip, shared-secret, labels, compound)",
+			Required: required,
+		},
+		&cli.StringFlag{
+			Name:     "action",
+			Usage:    "action (accept, hold, deny)",
+			Required: required,
+		},
+		&cli.IntFlag{
+			Name:     "priority",
+			Usage:    "priority (rules sorted in descending order)",
+			Required: required,
+		},
+		&cli.StringFlag{
+			Name:  "regex",
+			Usage: "regex for rules that accept it (cluster-name, source-ip)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "cidr",
+			Usage: "cidr block (ipv4 or ipv6) a source-ip rule matches, used instead of --regex",
+		},
+		&cli.StringFlag{
+			Name:  "selector",
+			Usage: "label selector a labels rule matches, e.g. \"region in (eu-west,eu-north),kubernetes.io/arch=arm64\"",
+		},
+		&cli.StringSliceFlag{
+			Name:  "secret-id",
+			Usage: "shared secret id a shared-secret rule accepts (default any stored secret)",
+		},
+		&cli.StringFlag{
+			Name:  "operator",
+			Usage: "how compound rule conditions combine (and, or)",
+			Value: "and",
+		},
+		&cli.StringSliceFlag{
+			Name:  "condition",
+			Usage: "compound rule condition, [!]cluster-name=<regex>, [!]source-ip=<regex>, [!]cidr=<cidr>[,<cidr>], [!]shared-secret[=<id>[,<id>]], [!]labels=<selector> or [!]all",
+		},
+		&cli.StringFlag{
+			Name:  "not-before",
+			Usage: "time (RFC 3339) the rule becomes active, empty to clear",
+		},
+		&cli.StringFlag{
+			Name:  "not-after",
+			Usage: "time (RFC 3339) the rule stops being active, empty to clear",
+		},
+		&cli.StringSliceFlag{
+			Name:  "window",
+			Usage: "window the rule is active in, \"<cron schedule> for <duration>\", e.g. \"0 22 * * 1-5 for 4h\"",
+		},
+		&cli.StringFlag{
+			Name:  "timezone",
+			Usage: "time zone window schedules are read in (default UTC)",
+		},
+		&cli.Int64Flag{
+			Name:  "max-matches",
+			Usage: "agents the rule may decide in total (default unlimited)",
+		},
+		&cli.Int64Flag{
+			Name:  "rate-limit",
+			Usage: "agents the rule may decide per --rate-period (default unlimited)",
+		},
+		&cli.StringFlag{
+			Name:  "rate-period",
+			Usage: "period the rate limit applies to, e.g. 1h",
+		},
+		&cli.BoolFlag{
+			Name:  "hold-over-quota",
+			Usage: "hold matching agents once a limit is reached, instead of not matching",
+		},
+	}, clusterFlags()...)
+}
+
+// applyFlags sets the fields of a rule from the flags that were given. changing
+// the type of a rule clears what it matched on.
+func applyFlags(c *cli.Context, rule *rpc.Rule) error {
+	if c.IsSet("type") {
+		switch c.String("type") {
+		case "all":
+			rule.Type = rpc.RuleType_All
+		case "cluster-name":
+			rule.Type = rpc.RuleType_ClusterName
+		case "shared-secret":
+			rule.Type = rpc.RuleType_SharedSecret
+		case "source-ip":
+			rule.Type = rpc.RuleType_SourceIP
+		case "labels":
+			rule.Type = rpc.RuleType_Labels
+		case "compound":
+			rule.Type = rpc.RuleType_Compound
+		default:
+			return fmt.Errorf("invalid rule type %s specified", c.String("type"))
+		}
+
+		rule.Regex = ""
+		rule.CIDRs = nil
+		rule.SecretIDs = nil
+		rule.Selector = ""
+		rule.Operator = rpc.Operator_And
+		rule.Conditions = nil
 	}
-	var ruleType rpc.RuleType
-	var ruleAction rpc.RuleAction
 
-	switch c.String("type") {
-	case "all":
-		ruleType = rpc.RuleType_All
-	case "cluster-name":
-		ruleType = rpc.RuleType_ClusterName
-	case "shared-secret":
-		ruleType = rpc.RuleType_SharedSecret
-	case "source-ip":
-		ruleType = rpc.RuleType_SourceIP
-	default:
-		log.Fatalf("invalid rule type %s specified", c.String("type"))
+	if c.IsSet("action") {
+		switch c.String("action") {
+		case "accept":
+			rule.Action = rpc.RuleAction_Accept
+		case "hold":
+			rule.Action = rpc.RuleAction_Hold
+		case "deny":
+			rule.Action = rpc.RuleAction_Deny
+		default:
+			return fmt.Errorf("invalid rule action %s specified", c.String("action"))
+		}
 	}
 
-	switch c.String("action") {
-	case "accept":
-		ruleAction = rpc.RuleAction_Accept
-	case "hold":
-		ruleAction = rpc.RuleAction_Hold
-	case "deny":
-		ruleAction = rpc.RuleAction_Deny
-	default:
-		log.Fatalf("invalid rule action %s specified", c.String("action"))
+	if c.IsSet("priority") {
+		rule.Priority = int32(c.Int("priority"))
+	}
+	if c.IsSet("regex") {
+		rule.Regex = c.String("regex")
+	}
+	if c.IsSet("cidr") {
+		rule.CIDRs = c.StringSlice("cidr")
+	}
+	if c.IsSet("secret-id") {
+		rule.SecretIDs = c.StringSlice("secret-id")
+	}
+	if c.IsSet("selector") {
+		rule.Selector = c.String("selector")
 	}
 
-	rule := &rpc.Rule{
-		Type:     ruleType,
-		Action:   ruleAction,
-		Priority: int32(c.Int("priority")),
-		Regex:    c.String("regex"),
+	if c.IsSet("operator") {
+		switch c.String("operator") {
+		case "and":
+			rule.Operator = rpc.Operator_And
+		case "or":
+			rule.Operator = rpc.Operator_Or
+		default:
+			return fmt.Errorf("invalid operator %s specified", c.String("operator"))
+		}
+	}
+	if c.IsSet("condition") {
+		rule.Conditions = nil
+		for _, v := range c.StringSlice("condition") {
+			condition, err := parseCondition(v)
+			if err != nil {
+				return err
+			}
+			rule.Conditions = append(rule.Conditions, condition)
+		}
+	}
+
+	if c.IsSet("not-before") {
+		rule.NotBefore = c.String("not-before")
+	}
+	if c.IsSet("not-after") {
+		rule.NotAfter = c.String("not-after")
+	}
+	if c.IsSet("window") {
+		rule.Windows = nil
+		for _, v := range c.StringSlice("window") {
+			window, err := parseWindow(v)
+			if err != nil {
+				return err
+			}
+			rule.Windows = append(rule.Windows, window)
+		}
+	}
+	if c.IsSet("timezone") {
+		rule.TimeZone = c.String("timezone")
+	}
+
+	if c.IsSet("max-matches") {
+		rule.MaxMatches = c.Int64("max-matches")
+	}
+	if c.IsSet("rate-limit") {
+		rule.RateLimit = c.Int64("rate-limit")
+	}
+	if c.IsSet("rate-period") {
+		rule.RatePeriod = c.String("rate-period")
+	}
+	if c.IsSet("hold-over-quota") {
+		rule.HoldOverQuota = c.Bool("hold-over-quota")
+	}
+
+	return applyClusterFlags(c, rule)
+}
+
+func createRule(c *cli.Context) error {
+	_, rulesClient, err := client.Setup(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rule := &rpc.Rule{}
+	if err := applyFlags(c, rule); err != nil {
+		log.Fatal(err)
 	}
 
 	resp, err := rulesClient.AddRule(c.Context, rule)
@@ -100,7 +301,7 @@ func createRule(c *cli.Context) error {
 	}
 
 	if resp.Success {
-		fmt.Printf("rule created\n")
+		fmt.Printf("rule %s created\n", resp.ID)
 	} else {
 		fmt.Printf("unable to create rule\n")
 	}
@@ -108,17 +309,72 @@ func createRule(c *cli.Context) error {
 	return nil
 }
 
+func updateRule(c *cli.Context) error {
+	_, rulesClient, err := client.Setup(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if c.Args().First() == "" {
+		return fmt.Errorf("no rule id specified")
+	}
+
+	rule, err := rulesClient.GetRule(c.Context, &rpc.RuleID{ID: c.Args().First()})
+	if err != nil {
+		log.Fatalf("error while calling GetRule: %s", err)
+	}
+
+	if err := applyFlags(c, rule); err != nil {
+		log.Fatal(err)
+	}
+	if c.IsSet("resource-version") {
+		rule.ResourceVersion = c.Int64("resource-version")
+	}
+
+	updated, err := rulesClient.UpdateRule(c.Context, rule)
+	if err != nil {
+		log.Fatalf("error while calling UpdateRule: %s", err)
+	}
+
+	fmt.Printf("rule %s updated to version %d\n", updated.ID, updated.ResourceVersion)
+
+	return nil
+}
+
+func getRule(c *cli.Context) error {
+	_, rulesClient, err := client.Setup(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if c.Args().First() == "" {
+		return fmt.Errorf("no rule id specified")
+	}
+
+	rule, err := rulesClient.GetRule(c.Context, &rpc.RuleID{ID: c.Args().First()})
+	if err != nil {
+		log.Fatalf("error while calling GetRule: %s", err)
+	}
+
+	printRules(&rpc.RuleList{Rules: []*rpc.Rule{rule}})
+
+	if rule.Cluster != nil {
+		fmt.Printf("\ncluster settings:\n%s\n", describeCluster(rule.Cluster))
+	}
+
+	return nil
+}
+
 func deleteRule(c *cli.Context) error {
-	_, rulesClient, err := rpc.SetupClients(c.String("server"), c.Bool("insecure"), c.String("cacerts"))
+	_, rulesClient, err := client.Setup(c)
 	if err != nil {
 		log.Fatal(err)
 	}
 	if c.Args().First() == "" {
-		return fmt.Errorf("no index specified")
+		return fmt.Errorf("no rule id specified")
 	}
 
-	ri := &rpc.RuleIndex{
-		Index: int32(c.Int("index")),
+	ri := &rpc.RuleID{
+		ID:              c.Args().First(),
+		ResourceVersion: c.Int64("resource-version"),
 	}
 
 	resp, err := rulesClient.DeleteRule(c.Context, ri)
@@ -135,8 +391,35 @@ func deleteRule(c *cli.Context) error {
 	return nil
 }
 
+func testRules(c *cli.Context) error {
+	_, rulesClient, err := client.Setup(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	req := &rpc.EvaluateRequest{
+		AgentID:     c.String("agent"),
+		ClusterName: c.String("cluster-name"),
+		IP:          c.String("ip"),
+		Secret:      c.String("secret"),
+		At:          c.String("at"),
+	}
+	if req.Labels, err = parseKeyValues(c.StringSlice("label")); err != nil {
+		return err
+	}
+
+	resp, err := rulesClient.EvaluateRules(c.Context, req)
+	if err != nil {
+		log.Fatalf("error while calling EvaluateRules: %s", err)
+	}
+
+	printResults(resp)
+
+	return nil
+}
+
 func listRules(c *cli.Context) error {
-	_, rulesClient, err := rpc.SetupClients(c.String("server"), c.Bool("insecure"), c.String("cacerts"))
+	_, rulesClient, err := client.Setup(c)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -154,13 +437,77 @@ func printRules(rules *rpc.RuleList) {
 	tabwriter := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.RememberWidths)
 	defer tabwriter.Flush()
 
-	headers := []string{"INDEX", "PRIORITY", "TYPE", "ACTION", "REGEX"}
+	headers := []string{"ID", "VERSION", "PRIORITY", "TYPE", "ACTION", "MATCH", "ACTIVE", "WHEN", "MATCHES"}
+	_, err := fmt.Fprintf(tabwriter, "%s\n", strings.Join(headers, "\t"))
+	if err != nil {
+		log.Fatalf("failed to print headers")
+	}
+
+	for _, rule := range rules.Rules {
+		fmt.Fprintf(tabwriter, "%s\t%d\t%d\t%s\t%s\t%s\t%t\t%s\t%s\n", rule.ID, rule.ResourceVersion, rule.Priority, rule.Type, rule.Action, describeRule(rule), rule.Active, describeSchedule(rule), describeUsage(rule))
+	}
+}
+
+func printResults(resp *rpc.EvaluateResponse) {
+	tabwriter := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.RememberWidths)
+
+	headers := []string{"ID", "PRIORITY", "TYPE", "ACTION", "MATCH", "RESULT"}
 	_, err := fmt.Fprintf(tabwriter, "%s\n", strings.Join(headers, "\t"))
 	if err != nil {
 		log.Fatalf("failed to print headers")
 	}
 
-	for i, rule := range rules.Rules {
-		fmt.Fprintf(tabwriter, "%d\t%d\t%s\t%s\t%s\n", i, rule.Priority, rule.Type, rule.Action, rule.Regex)
+	for _, result := range resp.Results {
+		rule := result.Rule
+		fmt.Fprintf(tabwriter, "%s\t%d\t%s\t%s\t%s\t%s\n", rule.ID, rule.Priority, rule.Type, rule.Action, describeRule(rule), describeResult(result, rule.ID == resp.RuleID))
+	}
+	tabwriter.Flush()
+
+	fmt.Printf("\ndecision: %s (%s)\n", resp.Status, resp.Message)
+}
+
+// describeUsage describes how many agents a rule decided against its limits
+func describeUsage(r *rpc.Rule) string {
+	s := fmt.Sprintf("%d", r.Matches)
+	if r.MaxMatches > 0 {
+		s += fmt.Sprintf("/%d", r.MaxMatches)
+	}
+	if r.RateLimit > 0 {
+		s += fmt.Sprintf(" (%d/%d per %s)", r.RecentMatches, r.RateLimit, r.RatePeriod)
+	}
+	if r.HoldOverQuota && (r.MaxMatches > 0 || r.RateLimit > 0) {
+		s += " then hold"
+	}
+
+	return s
+}
+
+func describeResult(result *rpc.RuleResult, decided bool) string {
+	if result.Error != "" {
+		return "invalid: " + result.Error
+	}
+	if !result.Active {
+		return "inactive"
+	}
+
+	s := "no match"
+	if result.Matched {
+		s = "match"
+	}
+	if result.OverQuota {
+		s += ", over quota"
+	}
+	if decided {
+		s += " (decides)"
 	}
-}
\ No newline at end of file
+
+	if result.Rule.Type == rpc.RuleType_Compound {
+		conditions := make([]string, len(result.Conditions))
+		for i, c := range result.Conditions {
+			conditions[i] = fmt.Sprintf("%t", c)
+		}
+		s += " [" + strings.Join(conditions, " ") + "]"
+	}
+
+	return s
+}
@@ -0,0 +1,51 @@
+package rule
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/rpc"
+	"strings"
+)
+
+// parseWindow parses a window from the command line, a cron schedule followed
+// by "for" and a duration. the same syntax is used when printing rules.
+func parseWindow(s string) (*rpc.Window, error) {
+	i := strings.LastIndex(s, " for ")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid window %s, expected \"<cron schedule> for <duration>\"", s)
+	}
+
+	return &rpc.Window{
+		Schedule: strings.TrimSpace(s[:i]),
+		Duration: strings.TrimSpace(s[i+len(" for "):]),
+	}, nil
+}
+
+// describeSchedule describes when a rule is active, or "always"
+func describeSchedule(r *rpc.Rule) string {
+	var parts []string
+	if r.NotBefore != "" {
+		parts = append(parts, "from "+r.NotBefore)
+	}
+	if r.NotAfter != "" {
+		parts = append(parts, "until "+r.NotAfter)
+	}
+
+	if len(r.Windows) > 0 {
+		windows := make([]string, len(r.Windows))
+		for i, w := range r.Windows {
+			windows[i] = fmt.Sprintf("%s for %s", w.Schedule, w.Duration)
+		}
+
+		tz := r.TimeZone
+		if tz == "" {
+			tz = "UTC"
+		}
+		parts = append(parts, fmt.Sprintf("during %s (%s)", strings.Join(windows, ", "), tz))
+	}
+
+	if len(parts) == 0 {
+		return "always"
+	}
+
+	return strings.Join(parts, " ")
+}
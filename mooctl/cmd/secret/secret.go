@@ -0,0 +1,112 @@
+package secret
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/mooctl/cmd/client"
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/liggitt/tabwriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"os"
+	"strings"
+)
+
+func LoadCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "secret",
+		Usage: "options for shared secrets",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list shared secrets",
+				Action: listSecrets,
+			},
+			{
+				Name:   "create",
+				Usage:  "mint a shared secret",
+				Action: createSecret,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "description",
+						Usage: "what the secret is for",
+					},
+				},
+			},
+			{
+				Name:      "revoke",
+				Usage:     "revoke shared secret",
+				ArgsUsage: "<id>",
+				Action:    revokeSecret,
+			},
+		},
+	}
+}
+
+func createSecret(c *cli.Context) error {
+	secretsClient, err := client.SetupSecrets(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	secret, err := secretsClient.CreateSecret(c.Context, &rpc.SecretRequest{Description: c.String("description")})
+	if err != nil {
+		log.Fatalf("error while calling CreateSecret: %s", err)
+	}
+
+	fmt.Printf("secret %s created, it will not be shown again:\n%s\n", secret.ID, secret.Secret)
+
+	return nil
+}
+
+func revokeSecret(c *cli.Context) error {
+	secretsClient, err := client.SetupSecrets(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if c.Args().First() == "" {
+		return fmt.Errorf("no secret id specified")
+	}
+
+	resp, err := secretsClient.DeleteSecret(c.Context, &rpc.SecretID{ID: c.Args().First()})
+	if err != nil {
+		log.Fatalf("error while calling DeleteSecret: %s", err)
+	}
+
+	if resp.Success {
+		fmt.Printf("secret revoked\n")
+	} else {
+		fmt.Printf("unable to revoke secret\n")
+	}
+
+	return nil
+}
+
+func listSecrets(c *cli.Context) error {
+	secretsClient, err := client.SetupSecrets(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	secrets, err := secretsClient.ListSecrets(c.Context, &rpc.Empty{})
+	if err != nil {
+		return err
+	}
+
+	printSecrets(secrets)
+
+	return nil
+}
+
+func printSecrets(secrets *rpc.SecretList) {
+	tabwriter := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.RememberWidths)
+	defer tabwriter.Flush()
+
+	headers := []string{"ID", "CREATED", "DESCRIPTION"}
+	_, err := fmt.Fprintf(tabwriter, "%s\n", strings.Join(headers, "\t"))
+	if err != nil {
+		log.Fatalf("failed to print headers")
+	}
+
+	for _, secret := range secrets.Secrets {
+		fmt.Fprintf(tabwriter, "%s\t%s\t%s\n", secret.ID, secret.Created, secret.Description)
+	}
+}
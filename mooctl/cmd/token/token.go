@@ -0,0 +1,127 @@
+package token
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/mooctl/cmd/client"
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/liggitt/tabwriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"os"
+	"strings"
+)
+
+func LoadCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "options for tokens",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list tokens",
+				Action: listTokens,
+			},
+			{
+				Name:   "create",
+				Usage:  "create token",
+				Action: createToken,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "role",
+						Usage:    "role granted by the token (agent, admin)",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Usage: "what the token is for",
+					},
+				},
+			},
+			{
+				Name:      "revoke",
+				Usage:     "revoke token",
+				ArgsUsage: "<id>",
+				Action:    revokeToken,
+			},
+		},
+	}
+}
+
+func createToken(c *cli.Context) error {
+	tokensClient, err := client.SetupTokens(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var role rpc.Role
+	switch c.String("role") {
+	case "agent":
+		role = rpc.Role_AgentRole
+	case "admin":
+		role = rpc.Role_AdminRole
+	default:
+		log.Fatalf("invalid role %s specified", c.String("role"))
+	}
+
+	token, err := tokensClient.CreateToken(c.Context, &rpc.TokenRequest{Role: role, Description: c.String("description")})
+	if err != nil {
+		log.Fatalf("error while calling CreateToken: %s", err)
+	}
+
+	fmt.Printf("token %s created, it will not be shown again:\n%s\n", token.ID, token.Secret)
+
+	return nil
+}
+
+func revokeToken(c *cli.Context) error {
+	tokensClient, err := client.SetupTokens(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if c.Args().First() == "" {
+		return fmt.Errorf("no token id specified")
+	}
+
+	resp, err := tokensClient.DeleteToken(c.Context, &rpc.TokenID{ID: c.Args().First()})
+	if err != nil {
+		log.Fatalf("error while calling DeleteToken: %s", err)
+	}
+
+	if resp.Success {
+		fmt.Printf("token revoked\n")
+	} else {
+		fmt.Printf("unable to revoke token\n")
+	}
+
+	return nil
+}
+
+func listTokens(c *cli.Context) error {
+	tokensClient, err := client.SetupTokens(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tokens, err := tokensClient.ListTokens(c.Context, &rpc.Empty{})
+	if err != nil {
+		return err
+	}
+
+	printTokens(tokens)
+
+	return nil
+}
+
+func printTokens(tokens *rpc.TokenList) {
+	tabwriter := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.RememberWidths)
+	defer tabwriter.Flush()
+
+	headers := []string{"ID", "ROLE", "CREATED", "DESCRIPTION"}
+	_, err := fmt.Fprintf(tabwriter, "%s\n", strings.Join(headers, "\t"))
+	if err != nil {
+		log.Fatalf("failed to print headers")
+	}
+
+	for _, token := range tokens.Tokens {
+		fmt.Fprintf(tabwriter, "%s\t%s\t%s\t%s\n", token.ID, token.Role, token.Created, token.Description)
+	}
+}
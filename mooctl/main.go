@@ -3,6 +3,8 @@ package main
 import (
 	"github.com/ebauman/moo/mooctl/cmd/agent"
 	"github.com/ebauman/moo/mooctl/cmd/rule"
+	"github.com/ebauman/moo/mooctl/cmd/secret"
+	"github.com/ebauman/moo/mooctl/cmd/token"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"os"
@@ -28,10 +30,27 @@ func main() {
 				Usage: "path to file containing ca certificate(s) (PEM format)",
 				EnvVars: []string{"MOO_SERVER_CACERTS"},
 			},
+			&cli.StringFlag{
+				Name: "client-cert",
+				Usage: "path to client certificate presented to the moo server (PEM format)",
+				EnvVars: []string{"MOO_CLIENT_CERT"},
+			},
+			&cli.StringFlag{
+				Name: "client-key",
+				Usage: "path to key for the client certificate (PEM format)",
+				EnvVars: []string{"MOO_CLIENT_KEY"},
+			},
+			&cli.StringFlag{
+				Name: "token",
+				Usage: "bearer token for the moo server",
+				EnvVars: []string{"MOO_TOKEN"},
+			},
 		},
 		Commands: []*cli.Command{
 			agent.LoadCommand(),
 			rule.LoadCommand(),
+			secret.LoadCommand(),
+			token.LoadCommand(),
 		},
 	}
 
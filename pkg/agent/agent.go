@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"fmt"
 	"github.com/ebauman/moo/pkg/config"
 	"github.com/ebauman/moo/pkg/kubernetes"
 	"github.com/ebauman/moo/pkg/rancher"
@@ -17,6 +18,8 @@ type RegistrationStatus string
 const (
 	Unregistered RegistrationStatus = "Unregistered"
 	Registered   RegistrationStatus = "Registered"
+
+	errorBackoff = 60 // seconds to wait after failing to talk to the server
 )
 
 type Agent struct {
@@ -57,10 +60,11 @@ func NewAgent(config *config.AgentConfig, kubernetes *kubernetes.KubernetesClien
 func (a *Agent) registerCluster(agentId string) (bool, error) {
 	rpcAgent := &rpc.Agent{
 		ID:          agentId,
-		Secret:      "", // TODO - implement
-		IP:          "", // TODO - implement (may need external ip service)
+		Secret:      a.config.Secret,
+		IP:          "", // the server takes this from the connection
 		ClusterName: a.config.ClusterName,
 		UseExisting: a.config.UseExisting,
+		Labels:      a.config.Labels,
 	}
 	resp, err := a.mooClient.RegisterAgent(a.context, rpcAgent)
 
@@ -88,75 +92,123 @@ func (a *Agent) ServerReconcile() {
 	a.log.Infof("agent id is %s", agentId)
 
 	for {
+		// the server pushes status changes to us for as long as the stream holds up
+		a.watchStatus(agentId)
+
+		// fall back to polling until the stream can be re-established
 		rpcID := &rpc.AgentID{ID: agentId}
 		status, err := a.mooClient.GetAgentStatus(a.context, rpcID)
 		if err != nil {
 			a.log.Errorf("error getting agent status from server: %v", err)
-			time.Sleep(time.Second * 60) // TODO - figure out error backoff
+			time.Sleep(time.Second * errorBackoff) // TODO - figure out error backoff
 			continue
 		}
 
-		if status.GetStatus() == rpc.Status_Unknown {
-			// have not registered
-			result, err := a.registerCluster(agentId)
-			if err != nil {
-				a.log.Errorf("error registering cluster with moo server : %v", err)
-				time.Sleep(time.Second * 60) // TODO - figure out error backoff
-				continue
-			}
-			if !result {
-				a.log.Errorf("cluster registration unsuccessful, server returned false")
-				time.Sleep(time.Second * 60) // TODO - figure out what to do if registration unsuccessful
-				continue
-			}
+		backoffTime, err := a.handleStatus(agentId, status)
+		if err != nil {
+			a.log.Error(err)
 		}
 
-		// if status is accepted, get the manifest url and proceed w/ reg
-		if status.GetStatus() == rpc.Status_Accepted {
-			manifestResponse, err := a.mooClient.GetManifestURL(a.context, rpcID)
-			if err != nil {
-				a.log.Errorf("error getting manifest from server: %v", err)
-				continue // TODO - should we be continuing, or failing?
-				// TODO - figure out error backoff?
-			}
+		a.log.Infof("backing off for %d seconds", backoffTime)
+		time.Sleep(time.Second * time.Duration(backoffTime))
+	}
+}
 
-			manfURL := manifestResponse.GetURL()
+// watchStatus follows status changes streamed by the server, returning once
+// the stream breaks or handling a status fails
+func (a *Agent) watchStatus(agentId string) {
+	ctx, cancel := context.WithCancel(a.context)
+	defer cancel()
 
-			yaml, err := rancher.DoGet(manfURL, "", "", a.config.CACerts, a.config.Insecure)
+	stream, err := a.mooClient.WatchAgentStatus(ctx, &rpc.AgentID{ID: agentId})
+	if err != nil {
+		a.log.Warnf("error watching agent status, falling back to polling: %v", err)
+		return
+	}
 
-			if err := a.kubernetes.ApplyManifest(yaml); err != nil {
-				a.log.Errorf("error applying rancher import manifest: %v", err)
-			}
+	for {
+		status, err := stream.Recv()
+		if err != nil {
+			a.log.Warnf("agent status stream broken, falling back to polling: %v", err)
+			return
+		}
 
-			// should be happily registered, so exit.
-			a.log.Infof("successfully registered cluster")
-			os.Exit(0)
+		if _, err := a.handleStatus(agentId, status); err != nil {
+			a.log.Error(err)
+			return
 		}
+	}
+}
 
-		if status.GetStatus() == rpc.Status_Denied {
-			a.log.Fatalf("server denied agent request, exiting")
+// handleStatus acts on a status from the server and returns how many seconds
+// to back off before asking again. if the agent is accepted and registration
+// succeeds, the process exits.
+func (a *Agent) handleStatus(agentId string, status *rpc.StatusResponse) (int32, error) {
+	switch status.GetStatus() {
+	case rpc.Status_Unknown:
+		// have not registered
+		result, err := a.registerCluster(agentId)
+		if err != nil {
+			return errorBackoff, fmt.Errorf("error registering cluster with moo server : %v", err)
+		}
+		if !result {
+			// TODO - figure out what to do if registration unsuccessful
+			return errorBackoff, fmt.Errorf("cluster registration unsuccessful, server returned false")
 		}
 
-		var backoffTime int32
+		return 0, nil
+	case rpc.Status_Accepted:
+		// if status is accepted, get the manifest url and proceed w/ reg
+		manfURL := status.GetManifestURL()
+		if manfURL == "" {
+			manifestResponse, err := a.mooClient.GetManifestURL(a.context, &rpc.AgentID{ID: agentId})
+			if err != nil {
+				return errorBackoff, fmt.Errorf("error getting manifest from server: %v", err)
+			}
+			manfURL = manifestResponse.GetURL()
+		}
 
-		switch status.GetStatus() {
-		case rpc.Status_Held:
-			backoffTime = status.GetHoldTime()
-		case rpc.Status_Error:
-			backoffTime = status.GetErrorTime()
-		case rpc.Status_Pending:
-			backoffTime = status.GetPendingTime()
+		if manfURL == "" {
+			// server has accepted us but hasn't created the cluster in rancher yet
+			a.log.Infof("agent accepted, waiting for manifest")
+			return status.GetPendingTime(), nil
 		}
 
-		if status.GetStatus() == rpc.Status_Error {
-			a.log.Errorf("server responded with status of %s: %s", status.GetStatus(), status.GetMessage())
-		} else {
-			a.log.Infof("server responded with status of %s", status.GetStatus())
+		yaml, err := rancher.DoGet(manfURL, "", "", a.config.CACerts, a.config.Insecure)
+		if err != nil {
+			return errorBackoff, fmt.Errorf("error downloading rancher import manifest: %v", err)
 		}
 
-		a.log.Infof("backing off for %d seconds", backoffTime)
-		time.Sleep(time.Second * time.Duration(backoffTime))
+		if err := a.kubernetes.ApplyManifest(yaml); err != nil {
+			a.log.Errorf("error applying rancher import manifest: %v", err)
+		}
+
+		// should be happily registered, so exit.
+		a.log.Infof("successfully registered cluster")
+		os.Exit(0)
+	case rpc.Status_Registered:
+		// the manifest was applied before and rancher has the cluster
+		a.log.Infof("cluster already registered with rancher: %s", status.GetMessage())
+		os.Exit(0)
+	case rpc.Status_Denied:
+		if !a.config.RetryDenied {
+			a.log.Fatalf("server denied agent request, exiting: %s", status.GetMessage())
+		}
+		// an admin may requeue us, check back as if held
+		a.log.Infof("server denied agent request: %s", status.GetMessage())
+		return status.GetHoldTime(), nil
+	case rpc.Status_Error:
+		a.log.Errorf("server responded with status of %s: %s", status.GetStatus(), status.GetMessage())
+		return status.GetErrorTime(), nil
+	case rpc.Status_Held:
+		a.log.Infof("server responded with status of %s", status.GetStatus())
+		return status.GetHoldTime(), nil
+	case rpc.Status_Pending:
+		a.log.Infof("server responded with status of %s", status.GetStatus())
+		return status.GetPendingTime(), nil
 	}
+
+	return 0, nil
 }
 
 func (a *Agent) StandaloneReconcile() {
@@ -1,72 +1,166 @@
 package agentstore
 
 import (
+	"github.com/ebauman/moo/pkg/storage"
 	"github.com/ebauman/moo/pkg/types"
+	"sync"
 )
 
+// Store is safe for concurrent use. every agent handed out is a deep copy
+// made by the backend, so callers are free to modify it and must write it
+// back with UpdateAgent (or UpdateAgentFunc) for the change to stick.
 type Store struct {
-	agents map[string]*types.Agent
+	mu      sync.RWMutex
+	backend storage.Backend
 
-	statusagents map[types.Status]map[string]*types.Agent
+	watchMu  sync.Mutex
+	watchers map[string]map[chan struct{}]bool
 }
 
+func NewStore(backend storage.Backend) *Store {
+	return &Store{
+		backend:  backend,
+		watchers: make(map[string]map[chan struct{}]bool, 0),
+	}
+}
 
-func NewStore() *Store {
-	agentMap := make(map[string]*types.Agent, 0)
-	statusAgentMap := make(map[types.Status]map[string]*types.Agent, 0)
-	statusAgentMap[types.StatusAccepted] = make(map[string]*types.Agent, 0)
-	statusAgentMap[types.StatusPending] = make(map[string]*types.Agent, 0)
-	statusAgentMap[types.StatusHeld] = make(map[string]*types.Agent, 0)
-	statusAgentMap[types.StatusDenied] = make(map[string]*types.Agent, 0)
-	statusAgentMap[types.StatusError] = make(map[string]*types.Agent, 0)
-	statusAgentMap[types.StatusUnknown] = make(map[string]*types.Agent, 0)
+// Watch returns a channel that is signalled whenever the agent with the given id
+// is written or removed. signals are coalesced, so a watcher should re-read the
+// agent when woken rather than count signals. cancel must be called when done.
+func (s *Store) Watch(id string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
 
-	return &Store{
-		agents: agentMap,
-		statusagents: statusAgentMap,
+	s.watchMu.Lock()
+	if s.watchers[id] == nil {
+		s.watchers[id] = make(map[chan struct{}]bool, 0)
 	}
+	s.watchers[id][ch] = true
+	s.watchMu.Unlock()
+
+	cancel := func() {
+		s.watchMu.Lock()
+		defer s.watchMu.Unlock()
+
+		delete(s.watchers[id], ch)
+		if len(s.watchers[id]) == 0 {
+			delete(s.watchers, id)
+		}
+	}
+
+	return ch, cancel
 }
 
-func (s *Store) AddAgent(a *types.Agent) {
-	s.agents[a.ID] = a
-	s.statusagents[a.Status][a.ID] = a
+func (s *Store) notify(id string) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	for ch := range s.watchers[id] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// already signalled
+		}
+	}
+}
+
+func (s *Store) AddAgent(a *types.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.backend.PutAgent(*a); err != nil {
+		return err
+	}
+
+	s.notify(a.ID)
+	return nil
 }
 
 func (s *Store) GetAgent(id string) *types.Agent {
-	return s.agents[id]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.backend.GetAgent(id)
+	if !ok {
+		return nil
+	}
+
+	return &a
 }
 
 func (s *Store) ListAgents() []*types.Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	agents := make([]*types.Agent, 0)
-	for _, v := range s.agents {
-		agents = append(agents, v)
+	for _, v := range s.backend.ListAgents() {
+		v := v
+		agents = append(agents, &v)
 	}
 
 	return agents
 }
 
 func (s *Store) ListAgentsByStatus(status types.Status) []*types.Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	agents := make([]*types.Agent, 0)
 
-	for _, v := range s.statusagents[status] {
-		agents = append(agents, v)
+	for _, v := range s.backend.ListAgents() {
+		if v.Status != status {
+			continue
+		}
+		v := v
+		agents = append(agents, &v)
 	}
 
 	return agents
 }
 
-func (s *Store) RemoveAgent(id string) {
-	delete(s.agents, id)
-	s.removeFromStatusMaps(id)
+func (s *Store) RemoveAgent(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.backend.DeleteAgent(id); err != nil {
+		return err
+	}
+
+	s.notify(id)
+	return nil
 }
 
-func (s *Store) removeFromStatusMaps(id string) {
-	for k := range s.statusagents {
-		delete(s.statusagents[k], id)
+func (s *Store) UpdateAgent(a *types.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.backend.PutAgent(*a); err != nil {
+		return err
 	}
+
+	s.notify(a.ID)
+	return nil
 }
 
-func (s *Store) UpdateAgent(a *types.Agent) {
-	s.removeFromStatusMaps(a.ID)
-	s.statusagents[a.Status][a.ID] = a
-}
\ No newline at end of file
+// UpdateAgentFunc atomically reads the current agent, applies fn to it and stores
+// the result. if fn returns false (or the agent doesn't exist) nothing is written.
+// the returned agent is a copy of what is stored after the call.
+func (s *Store) UpdateAgentFunc(id string, fn func(a *types.Agent) bool) (*types.Agent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.backend.GetAgent(id)
+	if !ok {
+		return nil, nil
+	}
+
+	if !fn(&a) {
+		return &a, nil
+	}
+
+	if err := s.backend.PutAgent(a); err != nil {
+		return nil, err
+	}
+
+	s.notify(id)
+	return &a, nil
+}
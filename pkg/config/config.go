@@ -8,8 +8,25 @@ type AgentConfig struct {
 	ID          string
 
 	ServerHostname string
+	ServerInsecure bool
 
-	CACerts string
+	CACerts    string
+	ClientCert string
+	ClientKey  string
+	Token      string
+
+	// shared secret presented at registration, resolved from one of these in order
+	Secret     string
+	SecretFile string
+	SecretRef  string // namespace/name/key of a kubernetes secret
+
+	// keep polling when denied, in case an admin requeues the agent
+	RetryDenied bool
+
+	// labels reported at registration. unless NoAutoLabels is set, labels
+	// collected from the cluster are added to those given.
+	Labels       map[string]string
+	NoAutoLabels bool
 
 	CattleConfig
 	RancherConfig
@@ -31,9 +48,36 @@ type RancherConfig struct {
 
 type ServerConfig struct {
 	RancherConfig
-	TLSCert     string
-	TLSKey      string
-	HoldTime    int32
-	PendingTime int32
-	ErrorTime   int32
+	TLSCert string
+	TLSKey  string
+	// when set, client certificates signed by these cas are verified
+	TLSClientCA       string
+	RequireClientCert bool
+	// bootstrap admin credentials, further tokens are created with mooctl
+	AdminToken       string
+	AdminIdentities  []string
+	RequireAgentAuth bool
+	// callers in these ranges may speak for others with the proxy protocol or x-forwarded-for
+	TrustedProxies []string
+	ProxyProtocol  bool
+	HoldTime       int32
+	PendingTime    int32
+	ErrorTime      int32
+	ResyncTime     int32
+	Workers        int
+	// what happens to agents no rule matches (pending, hold, deny), and to
+	// agents still pending after PendingTimeout seconds (hold, deny)
+	DefaultAction        string
+	PendingTimeout       int32
+	PendingTimeoutAction string
+	// how rancher clusters are named, a text/template executed per agent, and
+	// what happens when the name is taken (fail, suffix, adopt)
+	ClusterNameTemplate string
+	NameCollision       string
+	Storage             string
+	StoragePath         string
+
+	// used by kubernetes storage
+	KubeConfig       string
+	StorageNamespace string
 }
@@ -0,0 +1,45 @@
+package keys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// alphabet ids, tokens and secrets are made of
+const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// bytes at or above this would favour the start of the alphabet
+const maxByte = 256 - 256%len(alphabet)
+
+// Random returns n characters from a lowercase alphanumeric alphabet, read
+// from crypto/rand
+func Random(n int) (string, error) {
+	out := make([]byte, 0, n)
+	buf := make([]byte, n)
+
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= maxByte || len(out) == n {
+				continue
+			}
+			out = append(out, alphabet[int(b)%len(alphabet)])
+		}
+	}
+
+	return string(out), nil
+}
+
+// Hash is how tokens and secrets are kept: the hex encoded sha256 of the
+// value. an empty value hashes to an empty string.
+func Hash(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
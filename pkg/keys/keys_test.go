@@ -0,0 +1,39 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandom(t *testing.T) {
+	for _, n := range []int{0, 1, 6, 32, 100} {
+		s, err := Random(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s) != n {
+			t.Fatalf("got %d characters, want %d", len(s), n)
+		}
+		for _, c := range s {
+			if !strings.ContainsRune(alphabet, c) {
+				t.Fatalf("unexpected character %q in %s", c, s)
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		if got := Hash(tt.value); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -134,6 +135,21 @@ func (kc *KubernetesClient) CheckForDaemonset(namespace string, daemonset string
 	return false, nil
 }
 
+func (kc *KubernetesClient) GetSecretValue(namespace string, name string, key string) ([]byte, error) {
+	secret, err := kc.clientset.CoreV1().Secrets(namespace).Get(kc.context, name, v1.GetOptions{})
+	if err != nil {
+		kc.log.Debugf("error retrieving secret %s in namespace %s: %v", name, namespace, err)
+		return nil, err
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s in namespace %s has no key %s", name, namespace, key)
+	}
+
+	return value, nil
+}
+
 func (kc *KubernetesClient) createObject(yaml []byte) error {
 	obj := &unstructured.Unstructured{}
 
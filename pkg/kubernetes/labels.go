@@ -0,0 +1,78 @@
+package kubernetes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"strings"
+)
+
+// LabelPrefix prefixes the labels the agent derives itself
+const LabelPrefix = "moo.ebauman.io/"
+
+// well known node labels that are passed on when every node agrees on them
+var nodeLabels = []string{
+	"topology.kubernetes.io/region",
+	"topology.kubernetes.io/zone",
+	"kubernetes.io/arch",
+	"kubernetes.io/os",
+	"node.kubernetes.io/instance-type",
+}
+
+// CollectLabels describes the cluster for the moo server: its kubernetes
+// version, node count and provider, and the well known node labels its nodes
+// have in common
+func (kc *KubernetesClient) CollectLabels() (map[string]string, error) {
+	labels := map[string]string{}
+
+	version, err := kc.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	// build metadata, e.g. +k3s1, isn't allowed in a label value
+	labels[LabelPrefix+"kubernetes-version"] = strings.SplitN(version.GitVersion, "+", 2)[0]
+
+	nodes, err := kc.clientset.CoreV1().Nodes().List(kc.context, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	labels[LabelPrefix+"nodes"] = strconv.Itoa(len(nodes.Items))
+
+	for _, key := range nodeLabels {
+		if value := common(nodes.Items, func(n corev1.Node) string { return n.Labels[key] }); value != "" {
+			labels[key] = value
+		}
+	}
+
+	if provider := common(nodes.Items, providerOf); provider != "" {
+		labels[LabelPrefix+"provider"] = provider
+	}
+
+	return labels, nil
+}
+
+// common returns the value every node has, or "" if they differ
+func common(nodes []corev1.Node, value func(corev1.Node) string) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+
+	v := value(nodes[0])
+	for _, n := range nodes[1:] {
+		if value(n) != v {
+			return ""
+		}
+	}
+
+	return v
+}
+
+// providerOf returns the scheme of a node's provider id, e.g. aws for
+// aws:///us-east-1a/i-0123
+func providerOf(n corev1.Node) string {
+	if i := strings.Index(n.Spec.ProviderID, "://"); i > 0 {
+		return n.Spec.ProviderID[:i]
+	}
+
+	return ""
+}
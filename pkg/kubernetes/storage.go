@@ -0,0 +1,478 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	mooGroup   = "moo.ebauman.io"
+	mooVersion = "v1"
+
+	agentKind  = "MooAgent"
+	ruleKind   = "MooRule"
+	tokenKind  = "MooToken"
+	secretKind = "MooSharedSecret"
+
+	storageResync = 10 * time.Minute
+)
+
+var (
+	agentResource  = schema.GroupVersionResource{Group: mooGroup, Version: mooVersion, Resource: "mooagents"}
+	ruleResource   = schema.GroupVersionResource{Group: mooGroup, Version: mooVersion, Resource: "moorules"}
+	tokenResource  = schema.GroupVersionResource{Group: mooGroup, Version: mooVersion, Resource: "mootokens"}
+	secretResource = schema.GroupVersionResource{Group: mooGroup, Version: mooVersion, Resource: "moosharedsecrets"}
+)
+
+// StorageBackend stores agents as MooAgent, rules as MooRule, tokens as
+// MooToken and shared secrets as MooSharedSecret custom resources.
+// reads are served from informer caches kept current by watches, so the
+// server's reconcile loop never lists from the api server directly.
+type StorageBackend struct {
+	client    *KubernetesClient
+	namespace string
+
+	agents  dynamic.ResourceInterface
+	rules   dynamic.ResourceInterface
+	tokens  dynamic.ResourceInterface
+	secrets dynamic.ResourceInterface
+
+	agentCache  cache.Indexer
+	ruleCache   cache.Indexer
+	tokenCache  cache.Indexer
+	secretCache cache.Indexer
+
+	stop chan struct{}
+}
+
+func NewStorageBackend(kc *KubernetesClient, namespace string) (*StorageBackend, error) {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(kc.dynamic, storageResync, namespace, nil)
+	agentInformer := factory.ForResource(agentResource)
+	ruleInformer := factory.ForResource(ruleResource)
+	tokenInformer := factory.ForResource(tokenResource)
+	secretInformer := factory.ForResource(secretResource)
+
+	s := &StorageBackend{
+		client:      kc,
+		namespace:   namespace,
+		agents:      kc.dynamic.Resource(agentResource).Namespace(namespace),
+		rules:       kc.dynamic.Resource(ruleResource).Namespace(namespace),
+		tokens:      kc.dynamic.Resource(tokenResource).Namespace(namespace),
+		secrets:     kc.dynamic.Resource(secretResource).Namespace(namespace),
+		agentCache:  agentInformer.Informer().GetIndexer(),
+		ruleCache:   ruleInformer.Informer().GetIndexer(),
+		tokenCache:  tokenInformer.Informer().GetIndexer(),
+		secretCache: secretInformer.Informer().GetIndexer(),
+		stop:        make(chan struct{}),
+	}
+
+	factory.Start(s.stop)
+
+	kc.log.Infof("waiting for moo resource caches to sync in namespace %s", namespace)
+	for gvr, ok := range factory.WaitForCacheSync(s.stop) {
+		if !ok {
+			close(s.stop)
+			return nil, fmt.Errorf("failed to sync cache for %s", gvr.String())
+		}
+	}
+
+	return s, nil
+}
+
+func (s *StorageBackend) GetAgent(id string) (types.Agent, bool) {
+	obj, ok, err := s.agentCache.GetByKey(s.key(id))
+	if err != nil || !ok {
+		return types.Agent{}, false
+	}
+
+	a, err := agentFromObject(obj.(*unstructured.Unstructured))
+	if err != nil {
+		s.client.log.Errorf("error decoding %s %s: %v", agentKind, id, err)
+		return types.Agent{}, false
+	}
+
+	return a, true
+}
+
+func (s *StorageBackend) ListAgents() []types.Agent {
+	agents := make([]types.Agent, 0)
+	for _, obj := range s.agentCache.List() {
+		u := obj.(*unstructured.Unstructured)
+		a, err := agentFromObject(u)
+		if err != nil {
+			s.client.log.Errorf("error decoding %s %s: %v", agentKind, u.GetName(), err)
+			continue
+		}
+		agents = append(agents, a)
+	}
+
+	return agents
+}
+
+func (s *StorageBackend) PutAgent(a types.Agent) error {
+	return s.put(s.agents, s.agentCache, agentKind, a.ID, a)
+}
+
+func (s *StorageBackend) DeleteAgent(id string) error {
+	return s.delete(s.agents, s.agentCache, id)
+}
+
+// ListRules returns rules in evaluation order. rules may be created by hand or by
+// gitops tooling so ordering is derived from the objects themselves: priority
+// descending, then oldest first, then by name. the resource version of a rule
+// is the generation of its object, which the api server bumps on every change
+// to the spec, including those made with kubectl. its creation time is the
+// object's.
+func (s *StorageBackend) ListRules() []types.Rule {
+	objs := s.sortedRuleObjects()
+
+	rules := make([]types.Rule, 0, len(objs))
+	for _, u := range objs {
+		r, err := ruleFromObject(u)
+		if err != nil {
+			s.client.log.Errorf("error decoding %s %s: %v", ruleKind, u.GetName(), err)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	return rules
+}
+
+// PutRules creates or updates the MooRule objects of the given rules. objects
+// are named by rule id. those whose spec already matches are left alone, and
+// objects not in the list are never touched: they may be rules created since
+// the list was read, or rules that can't be decoded, e.g. a typo made with
+// kubectl, which are skipped by ListRules and must not be lost. the usage of a
+// rule is kept in the status subresource, so tools managing the spec, e.g.
+// gitops, neither revert it nor see drift each time a rule matches.
+func (s *StorageBackend) PutRules(rules []types.Rule) error {
+	existing := make(map[string]*unstructured.Unstructured)
+	for _, u := range s.sortedRuleObjects() {
+		existing[u.GetName()] = u
+	}
+
+	for _, r := range rules {
+		if r.ID == "" {
+			return fmt.Errorf("%s has no id", ruleKind)
+		}
+
+		var current *types.Rule
+		if u, ok := existing[r.ID]; ok {
+			c, err := ruleFromObject(u)
+			if err != nil {
+				return fmt.Errorf("not overwriting %s %s, it can't be decoded: %v", ruleKind, r.ID, err)
+			}
+			current = &c
+		}
+
+		if current == nil || !reflect.DeepEqual(ruleSpec(*current), ruleSpec(r)) {
+			spec, err := toMap(ruleSpec(r))
+			if err != nil {
+				return err
+			}
+			// kept in the object's metadata and status
+			delete(spec, "usage")
+			delete(spec, "resourceVersion")
+			delete(spec, "created")
+			if err := s.put(s.rules, s.ruleCache, ruleKind, r.ID, spec); err != nil {
+				return err
+			}
+		}
+
+		currentUsage := types.RuleUsage{}
+		if current != nil {
+			currentUsage = current.Usage
+		}
+		if !usageEqual(currentUsage, r.Usage) {
+			if err := s.putRuleUsage(r.ID, r.Usage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *StorageBackend) DeleteRule(id string) error {
+	return s.delete(s.rules, s.ruleCache, id)
+}
+
+func (s *StorageBackend) GetToken(id string) (types.Token, bool) {
+	obj, ok, err := s.tokenCache.GetByKey(s.key(id))
+	if err != nil || !ok {
+		return types.Token{}, false
+	}
+
+	t := types.Token{}
+	if err := fromSpec(obj.(*unstructured.Unstructured), &t); err != nil {
+		s.client.log.Errorf("error decoding %s %s: %v", tokenKind, id, err)
+		return types.Token{}, false
+	}
+
+	return t, true
+}
+
+func (s *StorageBackend) ListTokens() []types.Token {
+	tokens := make([]types.Token, 0)
+	for _, obj := range s.tokenCache.List() {
+		u := obj.(*unstructured.Unstructured)
+		t := types.Token{}
+		if err := fromSpec(u, &t); err != nil {
+			s.client.log.Errorf("error decoding %s %s: %v", tokenKind, u.GetName(), err)
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens
+}
+
+func (s *StorageBackend) PutToken(t types.Token) error {
+	return s.put(s.tokens, s.tokenCache, tokenKind, t.ID, t)
+}
+
+func (s *StorageBackend) DeleteToken(id string) error {
+	return s.delete(s.tokens, s.tokenCache, id)
+}
+
+func (s *StorageBackend) GetSecret(id string) (types.Secret, bool) {
+	obj, ok, err := s.secretCache.GetByKey(s.key(id))
+	if err != nil || !ok {
+		return types.Secret{}, false
+	}
+
+	secret := types.Secret{}
+	if err := fromSpec(obj.(*unstructured.Unstructured), &secret); err != nil {
+		s.client.log.Errorf("error decoding %s %s: %v", secretKind, id, err)
+		return types.Secret{}, false
+	}
+
+	return secret, true
+}
+
+func (s *StorageBackend) ListSecrets() []types.Secret {
+	secrets := make([]types.Secret, 0)
+	for _, obj := range s.secretCache.List() {
+		u := obj.(*unstructured.Unstructured)
+		secret := types.Secret{}
+		if err := fromSpec(u, &secret); err != nil {
+			s.client.log.Errorf("error decoding %s %s: %v", secretKind, u.GetName(), err)
+			continue
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets
+}
+
+func (s *StorageBackend) PutSecret(secret types.Secret) error {
+	return s.put(s.secrets, s.secretCache, secretKind, secret.ID, secret)
+}
+
+func (s *StorageBackend) DeleteSecret(id string) error {
+	return s.delete(s.secrets, s.secretCache, id)
+}
+
+func (s *StorageBackend) Close() error {
+	close(s.stop)
+	return nil
+}
+
+func (s *StorageBackend) sortedRuleObjects() []*unstructured.Unstructured {
+	objs := make([]*unstructured.Unstructured, 0)
+	for _, obj := range s.ruleCache.List() {
+		objs = append(objs, obj.(*unstructured.Unstructured))
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		pi, _, _ := unstructured.NestedInt64(objs[i].Object, "spec", "priority")
+		pj, _, _ := unstructured.NestedInt64(objs[j].Object, "spec", "priority")
+		if pi != pj {
+			return pi > pj
+		}
+		ti, tj := objs[i].GetCreationTimestamp(), objs[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+
+	return objs
+}
+
+// put creates or replaces the named object with in as its spec, and writes the
+// result through to the cache so it is visible to reads straight away
+func (s *StorageBackend) put(resource dynamic.ResourceInterface, indexer cache.Indexer, kind string, name string, in interface{}) error {
+	spec, err := toMap(in)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := resource.Get(s.client.context, name, v1.GetOptions{})
+		if errors.IsNotFound(err) {
+			obj := newObject(kind, name, s.namespace, spec)
+			created, err := resource.Create(s.client.context, obj, v1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			return indexer.Update(created)
+		}
+		if err != nil {
+			return err
+		}
+
+		existing.Object["spec"] = spec
+		updated, err := resource.Update(s.client.context, existing, v1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		return indexer.Update(updated)
+	})
+}
+
+// putRuleUsage writes the usage of a rule to its status
+func (s *StorageBackend) putRuleUsage(name string, usage types.RuleUsage) error {
+	status, err := toMap(usage)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := s.rules.Get(s.client.context, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		existing.Object["status"] = map[string]interface{}{"usage": status}
+		updated, err := s.rules.UpdateStatus(s.client.context, existing, v1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		return s.ruleCache.Update(updated)
+	})
+}
+
+func (s *StorageBackend) delete(resource dynamic.ResourceInterface, indexer cache.Indexer, name string) error {
+	err := resource.Delete(s.client.context, name, v1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if obj, ok, _ := indexer.GetByKey(s.key(name)); ok {
+		return indexer.Delete(obj)
+	}
+
+	return nil
+}
+
+func (s *StorageBackend) key(name string) string {
+	return s.namespace + "/" + name
+}
+
+func newObject(kind string, name string, namespace string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(mooGroup + "/" + mooVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+
+	return obj
+}
+
+func agentFromObject(u *unstructured.Unstructured) (types.Agent, error) {
+	a := types.Agent{}
+	err := fromSpec(u, &a)
+	return a, err
+}
+
+// the object name is the rule id, so rules created with kubectl need not set
+// one. usage is read from the status, never the spec.
+func ruleFromObject(u *unstructured.Unstructured) (types.Rule, error) {
+	r := types.Rule{}
+	if err := fromSpec(u, &r); err != nil {
+		return r, err
+	}
+	r.ID = u.GetName()
+	r.ResourceVersion = u.GetGeneration()
+	r.Created = u.GetCreationTimestamp().Time
+	r.Usage = types.RuleUsage{}
+
+	usage, ok, err := unstructured.NestedMap(u.Object, "status", "usage")
+	if err != nil || !ok {
+		return r, err
+	}
+
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return r, err
+	}
+
+	return r, json.Unmarshal(data, &r.Usage)
+}
+
+// ruleSpec is the part of a rule stored in the spec of its object
+func ruleSpec(r types.Rule) types.Rule {
+	r.ResourceVersion = 0
+	r.Created = time.Time{}
+	r.Usage = types.RuleUsage{}
+	return r
+}
+
+// usage read back from the api server loses the monotonic clock and location
+// of its times, so they are compared with Equal
+func usageEqual(a types.RuleUsage, b types.RuleUsage) bool {
+	if a.Matches != b.Matches || len(a.Recent) != len(b.Recent) {
+		return false
+	}
+	for i := range a.Recent {
+		if !a.Recent[i].Equal(b.Recent[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// spec is round-tripped through json so the json tags on the types package
+// double as the custom resource schema
+func fromSpec(u *unstructured.Unstructured, out interface{}) error {
+	spec, _, err := unstructured.NestedMap(u.Object, "spec")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+func toMap(in interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,56 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseCIDR parses a cidr block, ipv4 or ipv6, or a bare address as a block
+// of one. an ipv4 address written as ipv6, e.g. ::ffff:10.0.0.1, is a block
+// of one ipv4 address.
+func ParseCIDR(s string) (*net.IPNet, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid CIDR address: %s", s)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, n, err := net.ParseCIDR(s)
+	return n, err
+}
+
+// ParseCIDRs parses a list of cidr blocks and addresses like ParseCIDR
+func ParseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		n, err := ParseCIDR(c)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+// Contains reports whether ip falls within any of the networks
+func Contains(nets []*net.IPNet, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, n := range nets {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
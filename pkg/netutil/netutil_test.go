@@ -0,0 +1,70 @@
+package netutil
+
+import "testing"
+
+func TestParseCIDR(t *testing.T) {
+	tests := []struct {
+		cidr    string
+		want    string
+		wantErr bool
+	}{
+		{cidr: "10.0.0.0/8", want: "10.0.0.0/8"},
+		{cidr: "10.1.2.3/8", want: "10.0.0.0/8"},
+		{cidr: "192.168.1.1", want: "192.168.1.1/32"},
+		{cidr: "::ffff:192.168.1.1", want: "192.168.1.1/32"},
+		{cidr: "2001:db8::/32", want: "2001:db8::/32"},
+		{cidr: "2001:db8::1", want: "2001:db8::1/128"},
+		{cidr: "10.0.0.0/33", wantErr: true},
+		{cidr: "10.0.0", wantErr: true},
+		{cidr: "example.com", wantErr: true},
+		{cidr: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cidr, func(t *testing.T) {
+			n, err := ParseCIDR(tt.cidr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.String() != tt.want {
+				t.Fatalf("got %s, want %s", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	nets, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32", "fd00::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"11.0.0.1", false},
+		{"192.168.1.1", true},
+		{"192.168.1.2", false},
+		{"::ffff:10.0.0.1", true},
+		{"2001:db8:1::1", true},
+		{"2001:db9::1", false},
+		{"fd00::1", true},
+		{"fd00::2", false},
+		{"", false},
+		{"garbage", false},
+	}
+
+	for _, tt := range tests {
+		if got := Contains(nets, tt.ip); got != tt.want {
+			t.Errorf("%q: got %t, want %t", tt.ip, got, tt.want)
+		}
+	}
+}
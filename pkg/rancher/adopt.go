@@ -0,0 +1,102 @@
+package rancher
+
+import (
+	"fmt"
+	managementClient "github.com/rancher/types/client/management/v3"
+)
+
+// annotations binding a rancher cluster to the moo agent it belongs to. moo
+// sets them on the clusters it creates, admins may set them on existing
+// clusters to let an agent adopt them.
+const (
+	AgentIDAnnotation    = "moo.ebauman.io/agent-id"
+	SecretHashAnnotation = "moo.ebauman.io/secret-hash"
+	IdentityAnnotation   = "moo.ebauman.io/identity"
+)
+
+const clusterStateActive = "active"
+
+// Claim is what an agent brings to use a cluster that already exists
+type Claim struct {
+	AgentID    string
+	SecretHash string
+	Identity   string
+	// an admin allowed the agent to adopt any cluster
+	Override bool
+}
+
+// annotations binds a cluster to the agent of the claim
+func (c *Claim) annotations(existing map[string]string) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range existing {
+		annotations[k] = v
+	}
+
+	annotations[AgentIDAnnotation] = c.AgentID
+	setOrDelete(annotations, SecretHashAnnotation, c.SecretHash)
+	setOrDelete(annotations, IdentityAnnotation, c.Identity)
+
+	return annotations
+}
+
+func setOrDelete(m map[string]string, key string, value string) {
+	if value == "" {
+		delete(m, key)
+		return
+	}
+
+	m[key] = value
+}
+
+// check decides whether the claim proves the agent may adopt the cluster.
+// the secret hash and identity the cluster is annotated with must match the
+// claim. agent ids aren't secret, so a cluster without either is only bound
+// by its agent id. a cluster active with another agent is never adopted.
+func (c *Claim) check(cluster *managementClient.Cluster) error {
+	if c.Override {
+		return nil
+	}
+
+	bound := cluster.Annotations[AgentIDAnnotation]
+	hash := cluster.Annotations[SecretHashAnnotation]
+	identity := cluster.Annotations[IdentityAnnotation]
+
+	notBound := fmt.Errorf("cluster %s already exists in rancher and is not bound to this agent", cluster.Name)
+	switch {
+	case hash != "" && hash != c.SecretHash:
+		return notBound
+	case identity != "" && identity != c.Identity:
+		return notBound
+	case hash == "" && identity == "" && bound != c.AgentID:
+		return notBound
+	}
+
+	if bound != "" && bound != c.AgentID && cluster.State == clusterStateActive {
+		return fmt.Errorf("cluster %s is active with agent %s", cluster.Name, bound)
+	}
+
+	return nil
+}
+
+// adopt checks the claim on an existing cluster and binds the cluster to the
+// agent if it isn't already
+func (r *RancherServer) adopt(cluster *managementClient.Cluster, claim *Claim) error {
+	if err := claim.check(cluster); err != nil {
+		return err
+	}
+
+	if cluster.Annotations[AgentIDAnnotation] == claim.AgentID &&
+		cluster.Annotations[SecretHashAnnotation] == claim.SecretHash &&
+		cluster.Annotations[IdentityAnnotation] == claim.Identity {
+		return nil
+	}
+
+	_, err := r.client.Cluster.Update(cluster, map[string]interface{}{
+		managementClient.ClusterFieldAnnotations: claim.annotations(cluster.Annotations),
+	})
+	if err != nil {
+		return fmt.Errorf("error binding cluster %s to agent: %v", cluster.Name, err)
+	}
+
+	return nil
+}
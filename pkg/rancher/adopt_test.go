@@ -0,0 +1,109 @@
+package rancher
+
+import (
+	managementClient "github.com/rancher/types/client/management/v3"
+	"testing"
+)
+
+func TestClaimCheck(t *testing.T) {
+	claim := Claim{AgentID: "a1", SecretHash: "h1", Identity: "edge-1"}
+
+	tests := []struct {
+		name        string
+		claim       Claim
+		annotations map[string]string
+		state       string
+		wantErr     bool
+	}{
+		{
+			name:    "unannotated",
+			claim:   claim,
+			state:   "active",
+			wantErr: true,
+		},
+		{
+			name:        "bound by agent id only",
+			claim:       claim,
+			annotations: map[string]string{AgentIDAnnotation: "a1"},
+			state:       "active",
+		},
+		{
+			name:        "bound to another agent id",
+			claim:       claim,
+			annotations: map[string]string{AgentIDAnnotation: "a2"},
+			wantErr:     true,
+		},
+		{
+			name:        "agent id without the secret",
+			claim:       Claim{AgentID: "a1"},
+			annotations: map[string]string{AgentIDAnnotation: "a1", SecretHashAnnotation: "h1"},
+			wantErr:     true,
+		},
+		{
+			name:        "agent id with another identity",
+			claim:       Claim{AgentID: "a1", SecretHash: "h1", Identity: "edge-2"},
+			annotations: map[string]string{AgentIDAnnotation: "a1", SecretHashAnnotation: "h1", IdentityAnnotation: "edge-1"},
+			wantErr:     true,
+		},
+		{
+			name:        "all credentials match",
+			claim:       claim,
+			annotations: map[string]string{AgentIDAnnotation: "a1", SecretHashAnnotation: "h1", IdentityAnnotation: "edge-1"},
+			state:       "active",
+		},
+		{
+			name:        "secret of an unbound cluster",
+			claim:       claim,
+			annotations: map[string]string{SecretHashAnnotation: "h1"},
+			state:       "active",
+		},
+		{
+			name:        "secret of a cluster pending with another agent",
+			claim:       claim,
+			annotations: map[string]string{AgentIDAnnotation: "a2", SecretHashAnnotation: "h1"},
+			state:       "pending",
+		},
+		{
+			name:        "secret of a cluster active with another agent",
+			claim:       claim,
+			annotations: map[string]string{AgentIDAnnotation: "a2", SecretHashAnnotation: "h1"},
+			state:       "active",
+			wantErr:     true,
+		},
+		{
+			name:        "override",
+			claim:       Claim{AgentID: "a1", Override: true},
+			annotations: map[string]string{AgentIDAnnotation: "a2", SecretHashAnnotation: "h2"},
+			state:       "active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &managementClient.Cluster{Name: "edge", Annotations: tt.annotations, State: tt.state}
+			err := tt.claim.check(cluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("check() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClaimAnnotations(t *testing.T) {
+	existing := map[string]string{"owner": "ops", SecretHashAnnotation: "old", IdentityAnnotation: "old"}
+	claim := Claim{AgentID: "a1", SecretHash: "h1"}
+
+	got := claim.annotations(existing)
+	want := map[string]string{"owner": "ops", AgentIDAnnotation: "a1", SecretHashAnnotation: "h1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("annotation %s = %q, want %q", k, got[k], v)
+		}
+	}
+	if existing[SecretHashAnnotation] != "old" {
+		t.Errorf("existing annotations were modified")
+	}
+}
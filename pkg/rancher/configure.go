@@ -0,0 +1,105 @@
+package rancher
+
+import (
+	"fmt"
+	mooTypes "github.com/ebauman/moo/pkg/types"
+	"github.com/rancher/norman/types"
+	managementClient "github.com/rancher/types/client/management/v3"
+	"strings"
+)
+
+// ConfigureCluster creates the projects and cluster role template bindings of
+// the settings in a cluster. projects and bindings that already exist are left
+// alone, so it is safe to call again. every project and binding is attempted,
+// the error lists all that failed.
+func (r *RancherServer) ConfigureCluster(clusterID string, settings *mooTypes.ClusterSettings) error {
+	if settings == nil {
+		return nil
+	}
+
+	var failed []string
+
+	for _, p := range settings.Projects {
+		if err := r.ensureProject(clusterID, p); err != nil {
+			failed = append(failed, fmt.Sprintf("project %s: %v", p.Name, err))
+		}
+	}
+
+	for _, m := range settings.Members {
+		if err := r.ensureMember(clusterID, m); err != nil {
+			failed = append(failed, fmt.Sprintf("role %s for %s: %v", m.Role, memberID(m), err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+func (r *RancherServer) ensureProject(clusterID string, p mooTypes.ClusterProject) error {
+	filters := map[string]interface{}{
+		managementClient.ProjectFieldClusterID: clusterID,
+		managementClient.ProjectFieldName:      p.Name,
+	}
+	existing, err := r.client.Project.List(&types.ListOpts{Filters: filters})
+	if err != nil {
+		return err
+	}
+	if len(existing.Data) > 0 {
+		return nil
+	}
+
+	_, err = r.client.Project.Create(&managementClient.Project{
+		ClusterID:   clusterID,
+		Name:        p.Name,
+		Description: p.Description,
+	})
+
+	return err
+}
+
+func (r *RancherServer) ensureMember(clusterID string, m mooTypes.ClusterMember) error {
+	filters := map[string]interface{}{
+		managementClient.ClusterRoleTemplateBindingFieldClusterID:      clusterID,
+		managementClient.ClusterRoleTemplateBindingFieldRoleTemplateID: m.Role,
+	}
+	switch {
+	case m.UserID != "":
+		filters[managementClient.ClusterRoleTemplateBindingFieldUserID] = m.UserID
+	case m.UserPrincipalID != "":
+		filters[managementClient.ClusterRoleTemplateBindingFieldUserPrincipalID] = m.UserPrincipalID
+	default:
+		filters[managementClient.ClusterRoleTemplateBindingFieldGroupPrincipalID] = m.GroupPrincipalID
+	}
+
+	existing, err := r.client.ClusterRoleTemplateBinding.List(&types.ListOpts{Filters: filters})
+	if err != nil {
+		return err
+	}
+	if len(existing.Data) > 0 {
+		return nil
+	}
+
+	_, err = r.client.ClusterRoleTemplateBinding.Create(&managementClient.ClusterRoleTemplateBinding{
+		ClusterID:        clusterID,
+		RoleTemplateID:   m.Role,
+		UserID:           m.UserID,
+		UserPrincipalID:  m.UserPrincipalID,
+		GroupPrincipalID: m.GroupPrincipalID,
+	})
+
+	return err
+}
+
+func memberID(m mooTypes.ClusterMember) string {
+	switch {
+	case m.UserID != "":
+		return m.UserID
+	case m.UserPrincipalID != "":
+		return m.UserPrincipalID
+	default:
+		return m.GroupPrincipalID
+	}
+}
@@ -0,0 +1,22 @@
+package rancher
+
+import "fmt"
+
+// FreeClusterName returns name if no cluster in rancher has it, or else the
+// first of name-2, name-3, ... that is free. at most attempts names are tried.
+// the name is not reserved, a cluster created in between still collides.
+func (r *RancherServer) FreeClusterName(name string, attempts int) (string, error) {
+	candidate := name
+	for i := 2; i <= attempts+1; i++ {
+		cluster, err := r.checkForCluster(candidate)
+		if err != nil {
+			return "", err
+		}
+		if cluster == nil {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+
+	return "", fmt.Errorf("no free cluster name for %s after %d attempts", name, attempts)
+}
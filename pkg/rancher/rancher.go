@@ -3,6 +3,7 @@ package rancher
 import (
 	"fmt"
 	"github.com/ebauman/moo/pkg/config"
+	mooTypes "github.com/ebauman/moo/pkg/types"
 	"github.com/google/martian/log"
 	"github.com/rancher/norman/clientbase"
 	"github.com/rancher/norman/types"
@@ -46,39 +47,40 @@ func NewServer(config *config.RancherConfig) (*RancherServer, error) {
 	return server, nil
 }
 
-func (r *RancherServer) reconcile(clusterName string, useExisting bool) (string, error) {
+// Reconcile returns the id of the named cluster, creating it with the given
+// settings if it doesn't exist, and whether it was created. settings may be nil.
+// with a claim, created clusters are bound to the claiming agent and existing
+// clusters are only used if the claim proves the agent may adopt them.
+func (r *RancherServer) Reconcile(clusterName string, useExisting bool, settings *mooTypes.ClusterSettings, claim *Claim) (string, bool, error) {
 	cluster, err := r.checkForCluster(clusterName)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	if cluster != nil && !useExisting {
-		return "", fmt.Errorf("cluster %s already exists in rancher and use existing is false", clusterName)
+		return "", false, fmt.Errorf("cluster %s already exists in rancher and use existing is false", clusterName)
 	}
 
-	if cluster == nil {
-		// need to create the cluster
-		cluster, err = r.registerCluster(clusterName)
-		if err != nil {
-			return "", fmt.Errorf("error registering cluster with rancher: %v", err)
+	if cluster != nil {
+		if claim != nil {
+			if err := r.adopt(cluster, claim); err != nil {
+				return "", false, err
+			}
 		}
+		return cluster.ID, false, nil
 	}
 
-	return cluster.ID, nil
-}
-
-func (r *RancherServer) ReconcileToURL(clusterName string, useExisting bool) (string, error) {
-	id, err := r.reconcile(clusterName, useExisting)
-	
+	// need to create the cluster
+	cluster, err = r.registerCluster(clusterName, settings, claim)
 	if err != nil {
-		return "", err
+		return "", false, fmt.Errorf("error registering cluster with rancher: %v", err)
 	}
-	
-	return r.GetManifestURLForCluster(id)
+
+	return cluster.ID, true, nil
 }
 
 func (r *RancherServer) ReconcileToManifest(clusterName string, useExisting bool) ([]byte, error) {
-	id, err := r.reconcile(clusterName, useExisting)
+	id, _, err := r.Reconcile(clusterName, useExisting, nil, nil)
 	
 	if err != nil {
 		return nil, err
@@ -112,15 +114,25 @@ func (r *RancherServer) checkForCluster(clusterName string) (*managementClient.C
 	return nil, nil // we did not find a cluster with this name
 }
 
-func (r *RancherServer) registerCluster(clusterName string) (*managementClient.Cluster, error) {
+func (r *RancherServer) registerCluster(clusterName string, settings *mooTypes.ClusterSettings, claim *Claim) (*managementClient.Cluster, error) {
+	if settings == nil {
+		settings = &mooTypes.ClusterSettings{}
+	}
+
 	// create a new cluster
 	cluster := &managementClient.Cluster{}
 
-	var f = false
+	var networkPolicy = settings.EnableNetworkPolicy
 	cluster.Name = clusterName
-	cluster.EnableClusterAlerting = false
-	cluster.EnableClusterMonitoring = false
-	cluster.EnableNetworkPolicy = &f // HACK
+	cluster.Description = settings.Description
+	cluster.Labels = settings.Labels
+	cluster.Annotations = settings.Annotations
+	if claim != nil {
+		cluster.Annotations = claim.annotations(settings.Annotations)
+	}
+	cluster.EnableClusterAlerting = settings.EnableClusterAlerting
+	cluster.EnableClusterMonitoring = settings.EnableClusterMonitoring
+	cluster.EnableNetworkPolicy = &networkPolicy // HACK
 	cluster.Type = "cluster"
 
 	created, err := r.client.Cluster.Create(cluster)
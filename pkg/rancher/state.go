@@ -0,0 +1,52 @@
+package rancher
+
+import (
+	"fmt"
+	"github.com/rancher/norman/clientbase"
+	"strings"
+)
+
+// ClusterState is how far rancher got with a cluster
+type ClusterState struct {
+	State  string
+	Active bool
+	// why the cluster isn't active yet, from its transitioning message and
+	// the conditions that aren't true
+	Message string
+}
+
+// IsNotFound reports whether an error from rancher means the object is gone
+func IsNotFound(err error) bool {
+	return clientbase.IsNotFound(err)
+}
+
+// GetClusterState looks up the state of a cluster in rancher
+func (r *RancherServer) GetClusterState(clusterID string) (*ClusterState, error) {
+	cluster, err := r.client.Cluster.ByID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ClusterState{
+		State:  cluster.State,
+		Active: cluster.State == clusterStateActive,
+	}
+
+	var reasons []string
+	if cluster.TransitioningMessage != "" {
+		reasons = append(reasons, cluster.TransitioningMessage)
+	}
+	for _, c := range cluster.Conditions {
+		if c.Status == "True" {
+			continue
+		}
+		reason := c.Type
+		if c.Message != "" {
+			reason = fmt.Sprintf("%s: %s", c.Type, c.Message)
+		}
+		reasons = append(reasons, reason)
+	}
+	state.Message = strings.Join(reasons, "; ")
+
+	return state, nil
+}
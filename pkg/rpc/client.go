@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -9,7 +10,34 @@ import (
 	"io/ioutil"
 )
 
-func LoadTLSCredentials(caCert string) (credentials.TransportCredentials, error) {
+// ClientOptions describe how to connect to a moo server
+type ClientOptions struct {
+	Hostname string
+	Insecure bool
+	CACerts  string
+
+	// optional client certificate, presented to servers that verify clients
+	ClientCert string
+	ClientKey  string
+
+	// optional bearer token sent with every call
+	Token string
+}
+
+type tokenCredentials struct {
+	token    string
+	insecure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return !t.insecure
+}
+
+func LoadTLSCredentials(caCert string, clientCert string, clientKey string) (credentials.TransportCredentials, error) {
 	var certPool *x509.CertPool
 	if len(caCert) > 0 {
 		certPool = x509.NewCertPool()
@@ -33,31 +61,52 @@ func LoadTLSCredentials(caCert string) (credentials.TransportCredentials, error)
 		RootCAs: certPool,
 	}
 
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, fmt.Errorf("both a client certificate and key are required")
+		}
+
+		cert, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %v", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
 	return credentials.NewTLS(config), nil
 }
 
-func setupGrpcClient(hostname string, insecure bool, cacert string) (*grpc.ClientConn, error) {
+func setupGrpcClient(opts ClientOptions) (*grpc.ClientConn, error) {
 	var conn *grpc.ClientConn
 	var err error
 
-	if insecure {
-		conn, err = grpc.Dial(hostname, grpc.WithInsecure())
+	dialOpts := make([]grpc.DialOption, 0)
+	if opts.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials{token: opts.Token, insecure: opts.Insecure}))
+	}
+
+	if opts.Insecure {
+		conn, err = grpc.Dial(opts.Hostname, append(dialOpts, grpc.WithInsecure())...)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		tlsConfig, err := LoadTLSCredentials(cacert)
+		tlsConfig, err := LoadTLSCredentials(opts.CACerts, opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = grpc.Dial(opts.Hostname, append(dialOpts, grpc.WithTransportCredentials(tlsConfig))...)
 		if err != nil {
 			return nil, err
 		}
-		conn, err = grpc.Dial(hostname, grpc.WithTransportCredentials(tlsConfig))
 	}
 
 	return conn, nil
 }
 
-func SetupMooClient(hostname string, insecure bool, cacert string) (MooClient, error) {
-	client, err := setupGrpcClient(hostname, insecure, cacert)
+func SetupMooClient(opts ClientOptions) (MooClient, error) {
+	client, err := setupGrpcClient(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -65,8 +114,8 @@ func SetupMooClient(hostname string, insecure bool, cacert string) (MooClient, e
 	return NewMooClient(client), nil
 }
 
-func SetupRulesClient(hostname string, insecure bool, cacert string) (RulesClient, error) {
-	client, err := setupGrpcClient(hostname, insecure, cacert)
+func SetupRulesClient(opts ClientOptions) (RulesClient, error) {
+	client, err := setupGrpcClient(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +123,8 @@ func SetupRulesClient(hostname string, insecure bool, cacert string) (RulesClien
 	return NewRulesClient(client), nil
 }
 
-func SetupClients(hostname string, insecure bool, cacert string) (MooClient, RulesClient, error) {
-	client, err := setupGrpcClient(hostname, insecure, cacert)
+func SetupClients(opts ClientOptions) (MooClient, RulesClient, error) {
+	client, err := setupGrpcClient(opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -85,3 +134,21 @@ func SetupClients(hostname string, insecure bool, cacert string) (MooClient, Rul
 
 	return mooClient, rulesClient, nil
 }
+
+func SetupTokensClient(opts ClientOptions) (TokensClient, error) {
+	client, err := setupGrpcClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTokensClient(client), nil
+}
+
+func SetupSecretsClient(opts ClientOptions) (SecretsClient, error) {
+	client, err := setupGrpcClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSecretsClient(client), nil
+}
@@ -32,12 +32,13 @@ const _ = proto.ProtoPackageIsVersion4
 type Status int32
 
 const (
-	Status_Unknown  Status = 0 // initial
-	Status_Accepted Status = 1 // yay!
-	Status_Held     Status = 2 // hold off
-	Status_Denied   Status = 3 // go away
-	Status_Pending  Status = 4 // hang on
-	Status_Error    Status = 5 // uh oh
+	Status_Unknown    Status = 0 // initial
+	Status_Accepted   Status = 1 // yay!
+	Status_Held       Status = 2 // hold off
+	Status_Denied     Status = 3 // go away
+	Status_Pending    Status = 4 // hang on
+	Status_Error      Status = 5 // uh oh
+	Status_Registered Status = 6 // accepted and active in rancher
 )
 
 // Enum value maps for Status.
@@ -49,14 +50,16 @@ var (
 		3: "Denied",
 		4: "Pending",
 		5: "Error",
+		6: "Registered",
 	}
 	Status_value = map[string]int32{
-		"Unknown":  0,
-		"Accepted": 1,
-		"Held":     2,
-		"Denied":   3,
-		"Pending":  4,
-		"Error":    5,
+		"Unknown":    0,
+		"Accepted":   1,
+		"Held":       2,
+		"Denied":     3,
+		"Pending":    4,
+		"Error":      5,
+		"Registered": 6,
 	}
 )
 
@@ -94,6 +97,8 @@ const (
 	RuleType_SharedSecret RuleType = 1
 	RuleType_ClusterName  RuleType = 2
 	RuleType_All          RuleType = 3
+	RuleType_Compound     RuleType = 4 // combines the rule's Conditions
+	RuleType_Labels       RuleType = 5 // matches the agent's labels against Selector
 )
 
 // Enum value maps for RuleType.
@@ -103,12 +108,16 @@ var (
 		1: "SharedSecret",
 		2: "ClusterName",
 		3: "All",
+		4: "Compound",
+		5: "Labels",
 	}
 	RuleType_value = map[string]int32{
 		"SourceIP":     0,
 		"SharedSecret": 1,
 		"ClusterName":  2,
 		"All":          3,
+		"Compound":     4,
+		"Labels":       5,
 	}
 )
 
@@ -188,6 +197,98 @@ func (RuleAction) EnumDescriptor() ([]byte, []int) {
 	return file_moo_proto_rawDescGZIP(), []int{2}
 }
 
+type Operator int32
+
+const (
+	Operator_And Operator = 0 // every condition matches
+	Operator_Or  Operator = 1 // any condition matches
+)
+
+// Enum value maps for Operator.
+var (
+	Operator_name = map[int32]string{
+		0: "And",
+		1: "Or",
+	}
+	Operator_value = map[string]int32{
+		"And": 0,
+		"Or":  1,
+	}
+)
+
+func (x Operator) Enum() *Operator {
+	p := new(Operator)
+	*p = x
+	return p
+}
+
+func (x Operator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operator) Descriptor() protoreflect.EnumDescriptor {
+	return file_moo_proto_enumTypes[3].Descriptor()
+}
+
+func (Operator) Type() protoreflect.EnumType {
+	return &file_moo_proto_enumTypes[3]
+}
+
+func (x Operator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operator.Descriptor instead.
+func (Operator) EnumDescriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{3}
+}
+
+type Role int32
+
+const (
+	Role_AgentRole Role = 0 // may register and poll for status
+	Role_AdminRole Role = 1 // may manage rules, agents and tokens
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "AgentRole",
+		1: "AdminRole",
+	}
+	Role_value = map[string]int32{
+		"AgentRole": 0,
+		"AdminRole": 1,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_moo_proto_enumTypes[4].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_moo_proto_enumTypes[4]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{4}
+}
+
 type AgentListResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -367,6 +468,70 @@ func (x *AgentID) GetID() string {
 	return ""
 }
 
+// an admin acting on an agent, the reason is recorded in its status message
+type AgentAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID            string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Reason        string `protobuf:"bytes,2,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	AllowAdoption bool   `protobuf:"varint,3,opt,name=AllowAdoption,proto3" json:"AllowAdoption,omitempty"` // on approval, lets the agent adopt an existing rancher cluster without proof
+}
+
+func (x *AgentAction) Reset() {
+	*x = AgentAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AgentAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentAction) ProtoMessage() {}
+
+func (x *AgentAction) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentAction.ProtoReflect.Descriptor instead.
+func (*AgentAction) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AgentAction) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *AgentAction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AgentAction) GetAllowAdoption() bool {
+	if x != nil {
+		return x.AllowAdoption
+	}
+	return false
+}
+
 type StatusResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -377,12 +542,13 @@ type StatusResponse struct {
 	HoldTime    int32  `protobuf:"varint,3,opt,name=HoldTime,proto3" json:"HoldTime,omitempty"`
 	PendingTime int32  `protobuf:"varint,4,opt,name=PendingTime,proto3" json:"PendingTime,omitempty"`
 	ErrorTime   int32  `protobuf:"varint,5,opt,name=ErrorTime,proto3" json:"ErrorTime,omitempty"`
+	ManifestURL string `protobuf:"bytes,6,opt,name=ManifestURL,proto3" json:"ManifestURL,omitempty"`
 }
 
 func (x *StatusResponse) Reset() {
 	*x = StatusResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[4]
+		mi := &file_moo_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -395,7 +561,7 @@ func (x *StatusResponse) String() string {
 func (*StatusResponse) ProtoMessage() {}
 
 func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moo_proto_msgTypes[4]
+	mi := &file_moo_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,7 +574,7 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_moo_proto_rawDescGZIP(), []int{4}
+	return file_moo_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *StatusResponse) GetStatus() Status {
@@ -446,27 +612,42 @@ func (x *StatusResponse) GetErrorTime() int32 {
 	return 0
 }
 
+func (x *StatusResponse) GetManifestURL() string {
+	if x != nil {
+		return x.ManifestURL
+	}
+	return ""
+}
+
 type Agent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID            string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Secret        string `protobuf:"bytes,2,opt,name=Secret,proto3" json:"Secret,omitempty"`
-	IP            string `protobuf:"bytes,3,opt,name=IP,proto3" json:"IP,omitempty"`
-	Status        Status `protobuf:"varint,4,opt,name=Status,proto3,enum=Status" json:"Status,omitempty"`
-	ManifestUrl   string `protobuf:"bytes,5,opt,name=ManifestUrl,proto3" json:"ManifestUrl,omitempty"`
-	StatusMessage string `protobuf:"bytes,6,opt,name=StatusMessage,proto3" json:"StatusMessage,omitempty"`
-	Completed     bool   `protobuf:"varint,7,opt,name=completed,proto3" json:"completed,omitempty"`
-	LastContact   string `protobuf:"bytes,8,opt,name=LastContact,proto3" json:"LastContact,omitempty"`
-	ClusterName   string `protobuf:"bytes,9,opt,name=ClusterName,proto3" json:"ClusterName,omitempty"`
-	UseExisting   bool   `protobuf:"varint,10,opt,name=UseExisting,proto3" json:"UseExisting,omitempty"`
+	ID                 string            `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Secret             string            `protobuf:"bytes,2,opt,name=Secret,proto3" json:"Secret,omitempty"` // shared secret presented at registration, never returned
+	IP                 string            `protobuf:"bytes,3,opt,name=IP,proto3" json:"IP,omitempty"`
+	Status             Status            `protobuf:"varint,4,opt,name=Status,proto3,enum=Status" json:"Status,omitempty"`
+	ManifestUrl        string            `protobuf:"bytes,5,opt,name=ManifestUrl,proto3" json:"ManifestUrl,omitempty"`
+	StatusMessage      string            `protobuf:"bytes,6,opt,name=StatusMessage,proto3" json:"StatusMessage,omitempty"`
+	Completed          bool              `protobuf:"varint,7,opt,name=completed,proto3" json:"completed,omitempty"`
+	LastContact        string            `protobuf:"bytes,8,opt,name=LastContact,proto3" json:"LastContact,omitempty"`
+	ClusterName        string            `protobuf:"bytes,9,opt,name=ClusterName,proto3" json:"ClusterName,omitempty"`
+	UseExisting        bool              `protobuf:"varint,10,opt,name=UseExisting,proto3" json:"UseExisting,omitempty"`
+	Identity           string            `protobuf:"bytes,11,opt,name=Identity,proto3" json:"Identity,omitempty"`                                                                                     // verified client certificate identity, set by the server
+	DecidedBy          string            `protobuf:"bytes,12,opt,name=DecidedBy,proto3" json:"DecidedBy,omitempty"`                                                                                   // admin who last set the status by hand, set by the server
+	Labels             map[string]string `protobuf:"bytes,13,rep,name=Labels,proto3" json:"Labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // reported by the agent, keys and values as kubernetes labels
+	RuleID             string            `protobuf:"bytes,14,opt,name=RuleID,proto3" json:"RuleID,omitempty"`                                                                                         // rule that last decided the agent, set by the server
+	Cluster            *ClusterSettings  `protobuf:"bytes,15,opt,name=Cluster,proto3" json:"Cluster,omitempty"`                                                                                       // settings the rancher cluster is created with, set by the server
+	RancherClusterID   string            `protobuf:"bytes,16,opt,name=RancherClusterID,proto3" json:"RancherClusterID,omitempty"`                                                                     // rancher cluster registered for the agent, set by the server
+	RancherClusterName string            `protobuf:"bytes,17,opt,name=RancherClusterName,proto3" json:"RancherClusterName,omitempty"`                                                                 // name of that cluster, from the cluster name template
+	AdoptionAllowedBy  string            `protobuf:"bytes,18,opt,name=AdoptionAllowedBy,proto3" json:"AdoptionAllowedBy,omitempty"`                                                                   // admin who allowed adopting an existing cluster, set by the server
 }
 
 func (x *Agent) Reset() {
 	*x = Agent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[5]
+		mi := &file_moo_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -479,7 +660,7 @@ func (x *Agent) String() string {
 func (*Agent) ProtoMessage() {}
 
 func (x *Agent) ProtoReflect() protoreflect.Message {
-	mi := &file_moo_proto_msgTypes[5]
+	mi := &file_moo_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -492,7 +673,7 @@ func (x *Agent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Agent.ProtoReflect.Descriptor instead.
 func (*Agent) Descriptor() ([]byte, []int) {
-	return file_moo_proto_rawDescGZIP(), []int{5}
+	return file_moo_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Agent) GetID() string {
@@ -565,6 +746,62 @@ func (x *Agent) GetUseExisting() bool {
 	return false
 }
 
+func (x *Agent) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *Agent) GetDecidedBy() string {
+	if x != nil {
+		return x.DecidedBy
+	}
+	return ""
+}
+
+func (x *Agent) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Agent) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *Agent) GetCluster() *ClusterSettings {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+func (x *Agent) GetRancherClusterID() string {
+	if x != nil {
+		return x.RancherClusterID
+	}
+	return ""
+}
+
+func (x *Agent) GetRancherClusterName() string {
+	if x != nil {
+		return x.RancherClusterName
+	}
+	return ""
+}
+
+func (x *Agent) GetAdoptionAllowedBy() string {
+	if x != nil {
+		return x.AdoptionAllowedBy
+	}
+	return ""
+}
+
 type RegisterResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -576,7 +813,7 @@ type RegisterResponse struct {
 func (x *RegisterResponse) Reset() {
 	*x = RegisterResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[6]
+		mi := &file_moo_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -589,7 +826,7 @@ func (x *RegisterResponse) String() string {
 func (*RegisterResponse) ProtoMessage() {}
 
 func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moo_proto_msgTypes[6]
+	mi := &file_moo_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -602,7 +839,7 @@ func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
 func (*RegisterResponse) Descriptor() ([]byte, []int) {
-	return file_moo_proto_rawDescGZIP(), []int{6}
+	return file_moo_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *RegisterResponse) GetSuccess() bool {
@@ -624,7 +861,7 @@ type ManifestResponse struct {
 func (x *ManifestResponse) Reset() {
 	*x = ManifestResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[7]
+		mi := &file_moo_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -637,7 +874,7 @@ func (x *ManifestResponse) String() string {
 func (*ManifestResponse) ProtoMessage() {}
 
 func (x *ManifestResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moo_proto_msgTypes[7]
+	mi := &file_moo_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,7 +887,7 @@ func (x *ManifestResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ManifestResponse.ProtoReflect.Descriptor instead.
 func (*ManifestResponse) Descriptor() ([]byte, []int) {
-	return file_moo_proto_rawDescGZIP(), []int{7}
+	return file_moo_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ManifestResponse) GetSuccess() bool {
@@ -672,16 +909,35 @@ type Rule struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type     RuleType   `protobuf:"varint,1,opt,name=Type,proto3,enum=RuleType" json:"Type,omitempty"`
-	Action   RuleAction `protobuf:"varint,2,opt,name=Action,proto3,enum=RuleAction" json:"Action,omitempty"`
-	Priority int32      `protobuf:"varint,3,opt,name=Priority,proto3" json:"Priority,omitempty"`
-	Regex    string     `protobuf:"bytes,4,opt,name=Regex,proto3" json:"Regex,omitempty"`
+	Type            RuleType         `protobuf:"varint,1,opt,name=Type,proto3,enum=RuleType" json:"Type,omitempty"`
+	Action          RuleAction       `protobuf:"varint,2,opt,name=Action,proto3,enum=RuleAction" json:"Action,omitempty"`
+	Priority        int32            `protobuf:"varint,3,opt,name=Priority,proto3" json:"Priority,omitempty"`
+	Regex           string           `protobuf:"bytes,4,opt,name=Regex,proto3" json:"Regex,omitempty"`
+	SecretIDs       []string         `protobuf:"bytes,5,rep,name=SecretIDs,proto3" json:"SecretIDs,omitempty"`               // SharedSecret rules, empty matches any stored secret
+	CIDRs           []string         `protobuf:"bytes,6,rep,name=CIDRs,proto3" json:"CIDRs,omitempty"`                       // SourceIP rules, ipv4 or ipv6 blocks used instead of Regex
+	Operator        Operator         `protobuf:"varint,7,opt,name=Operator,proto3,enum=Operator" json:"Operator,omitempty"`  // Compound rules
+	Conditions      []*Condition     `protobuf:"bytes,8,rep,name=Conditions,proto3" json:"Conditions,omitempty"`             // Compound rules
+	ID              string           `protobuf:"bytes,9,opt,name=ID,proto3" json:"ID,omitempty"`                             // set by the server
+	ResourceVersion int64            `protobuf:"varint,10,opt,name=ResourceVersion,proto3" json:"ResourceVersion,omitempty"` // set by the server, must match the stored rule on update
+	NotBefore       string           `protobuf:"bytes,11,opt,name=NotBefore,proto3" json:"NotBefore,omitempty"`              // RFC 3339, the rule is inactive before this time
+	NotAfter        string           `protobuf:"bytes,12,opt,name=NotAfter,proto3" json:"NotAfter,omitempty"`                // RFC 3339, the rule is inactive from this time
+	Windows         []*Window        `protobuf:"bytes,13,rep,name=Windows,proto3" json:"Windows,omitempty"`                  // the rule is inactive unless one is open
+	TimeZone        string           `protobuf:"bytes,14,opt,name=TimeZone,proto3" json:"TimeZone,omitempty"`                // time zone the window schedules are read in, default UTC
+	Active          bool             `protobuf:"varint,15,opt,name=Active,proto3" json:"Active,omitempty"`                   // set by the server, whether the rule applies now
+	MaxMatches      int64            `protobuf:"varint,16,opt,name=MaxMatches,proto3" json:"MaxMatches,omitempty"`           // agents the rule may decide in total, 0 is unlimited
+	RateLimit       int64            `protobuf:"varint,17,opt,name=RateLimit,proto3" json:"RateLimit,omitempty"`             // agents the rule may decide per RatePeriod, 0 is unlimited
+	RatePeriod      string           `protobuf:"bytes,18,opt,name=RatePeriod,proto3" json:"RatePeriod,omitempty"`            // e.g. 1h
+	HoldOverQuota   bool             `protobuf:"varint,19,opt,name=HoldOverQuota,proto3" json:"HoldOverQuota,omitempty"`     // hold matching agents once a limit is reached, instead of not matching
+	Matches         int64            `protobuf:"varint,20,opt,name=Matches,proto3" json:"Matches,omitempty"`                 // set by the server, agents decided in total
+	RecentMatches   int64            `protobuf:"varint,21,opt,name=RecentMatches,proto3" json:"RecentMatches,omitempty"`     // set by the server, agents decided in the current RatePeriod
+	Selector        string           `protobuf:"bytes,22,opt,name=Selector,proto3" json:"Selector,omitempty"`                // Labels rules, a kubernetes label selector
+	Cluster         *ClusterSettings `protobuf:"bytes,23,opt,name=Cluster,proto3" json:"Cluster,omitempty"`                  // Accept rules, applied to the clusters created in rancher
 }
 
 func (x *Rule) Reset() {
 	*x = Rule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[8]
+		mi := &file_moo_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -694,7 +950,7 @@ func (x *Rule) String() string {
 func (*Rule) ProtoMessage() {}
 
 func (x *Rule) ProtoReflect() protoreflect.Message {
-	mi := &file_moo_proto_msgTypes[8]
+	mi := &file_moo_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -707,7 +963,7 @@ func (x *Rule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Rule.ProtoReflect.Descriptor instead.
 func (*Rule) Descriptor() ([]byte, []int) {
-	return file_moo_proto_rawDescGZIP(), []int{8}
+	return file_moo_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Rule) GetType() RuleType {
@@ -738,77 +994,170 @@ func (x *Rule) GetRegex() string {
 	return ""
 }
 
-type RuleList struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *Rule) GetSecretIDs() []string {
+	if x != nil {
+		return x.SecretIDs
+	}
+	return nil
+}
 
-	Rules []*Rule `protobuf:"bytes,1,rep,name=Rules,proto3" json:"Rules,omitempty"`
+func (x *Rule) GetCIDRs() []string {
+	if x != nil {
+		return x.CIDRs
+	}
+	return nil
 }
 
-func (x *RuleList) Reset() {
-	*x = RuleList{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Rule) GetOperator() Operator {
+	if x != nil {
+		return x.Operator
 	}
+	return Operator_And
 }
 
-func (x *RuleList) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Rule) GetConditions() []*Condition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
 }
 
-func (*RuleList) ProtoMessage() {}
+func (x *Rule) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
 
-func (x *RuleList) ProtoReflect() protoreflect.Message {
-	mi := &file_moo_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *Rule) GetResourceVersion() int64 {
+	if x != nil {
+		return x.ResourceVersion
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use RuleList.ProtoReflect.Descriptor instead.
-func (*RuleList) Descriptor() ([]byte, []int) {
-	return file_moo_proto_rawDescGZIP(), []int{9}
+func (x *Rule) GetNotBefore() string {
+	if x != nil {
+		return x.NotBefore
+	}
+	return ""
 }
 
-func (x *RuleList) GetRules() []*Rule {
+func (x *Rule) GetNotAfter() string {
 	if x != nil {
-		return x.Rules
+		return x.NotAfter
+	}
+	return ""
+}
+
+func (x *Rule) GetWindows() []*Window {
+	if x != nil {
+		return x.Windows
 	}
 	return nil
 }
 
-type AddResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *Rule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
 
-	Success bool `protobuf:"varint,1,opt,name=Success,proto3" json:"Success,omitempty"`
+func (x *Rule) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
 }
 
-func (x *AddResponse) Reset() {
-	*x = AddResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_moo_proto_msgTypes[10]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Rule) GetMaxMatches() int64 {
+	if x != nil {
+		return x.MaxMatches
 	}
+	return 0
 }
 
-func (x *AddResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Rule) GetRateLimit() int64 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
 }
 
-func (*AddResponse) ProtoMessage() {}
+func (x *Rule) GetRatePeriod() string {
+	if x != nil {
+		return x.RatePeriod
+	}
+	return ""
+}
 
-func (x *AddResponse) ProtoReflect() protoreflect.Message {
+func (x *Rule) GetHoldOverQuota() bool {
+	if x != nil {
+		return x.HoldOverQuota
+	}
+	return false
+}
+
+func (x *Rule) GetMatches() int64 {
+	if x != nil {
+		return x.Matches
+	}
+	return 0
+}
+
+func (x *Rule) GetRecentMatches() int64 {
+	if x != nil {
+		return x.RecentMatches
+	}
+	return 0
+}
+
+func (x *Rule) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *Rule) GetCluster() *ClusterSettings {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+type ClusterSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Description             string            `protobuf:"bytes,1,opt,name=Description,proto3" json:"Description,omitempty"`
+	Labels                  map[string]string `protobuf:"bytes,2,rep,name=Labels,proto3" json:"Labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations             map[string]string `protobuf:"bytes,3,rep,name=Annotations,proto3" json:"Annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	EnableClusterAlerting   bool              `protobuf:"varint,4,opt,name=EnableClusterAlerting,proto3" json:"EnableClusterAlerting,omitempty"`
+	EnableClusterMonitoring bool              `protobuf:"varint,5,opt,name=EnableClusterMonitoring,proto3" json:"EnableClusterMonitoring,omitempty"`
+	EnableNetworkPolicy     bool              `protobuf:"varint,6,opt,name=EnableNetworkPolicy,proto3" json:"EnableNetworkPolicy,omitempty"`
+	Projects                []*ClusterProject `protobuf:"bytes,7,rep,name=Projects,proto3" json:"Projects,omitempty"` // created once the cluster has been created
+	Members                 []*ClusterMember  `protobuf:"bytes,8,rep,name=Members,proto3" json:"Members,omitempty"`   // granted cluster roles once the cluster has been created
+}
+
+func (x *ClusterSettings) Reset() {
+	*x = ClusterSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClusterSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterSettings) ProtoMessage() {}
+
+func (x *ClusterSettings) ProtoReflect() protoreflect.Message {
 	mi := &file_moo_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -820,28 +1169,78 @@ func (x *AddResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddResponse.ProtoReflect.Descriptor instead.
-func (*AddResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ClusterSettings.ProtoReflect.Descriptor instead.
+func (*ClusterSettings) Descriptor() ([]byte, []int) {
 	return file_moo_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *AddResponse) GetSuccess() bool {
+func (x *ClusterSettings) GetDescription() string {
 	if x != nil {
-		return x.Success
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ClusterSettings) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ClusterSettings) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *ClusterSettings) GetEnableClusterAlerting() bool {
+	if x != nil {
+		return x.EnableClusterAlerting
 	}
 	return false
 }
 
-type DeleteResponse struct {
+func (x *ClusterSettings) GetEnableClusterMonitoring() bool {
+	if x != nil {
+		return x.EnableClusterMonitoring
+	}
+	return false
+}
+
+func (x *ClusterSettings) GetEnableNetworkPolicy() bool {
+	if x != nil {
+		return x.EnableNetworkPolicy
+	}
+	return false
+}
+
+func (x *ClusterSettings) GetProjects() []*ClusterProject {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+func (x *ClusterSettings) GetMembers() []*ClusterMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type ClusterProject struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Success bool `protobuf:"varint,1,opt,name=Success,proto3" json:"Success,omitempty"`
+	Name        string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=Description,proto3" json:"Description,omitempty"`
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
+func (x *ClusterProject) Reset() {
+	*x = ClusterProject{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_moo_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -849,13 +1248,13 @@ func (x *DeleteResponse) Reset() {
 	}
 }
 
-func (x *DeleteResponse) String() string {
+func (x *ClusterProject) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*ClusterProject) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+func (x *ClusterProject) ProtoReflect() protoreflect.Message {
 	mi := &file_moo_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -867,28 +1266,38 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ClusterProject.ProtoReflect.Descriptor instead.
+func (*ClusterProject) Descriptor() ([]byte, []int) {
 	return file_moo_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *DeleteResponse) GetSuccess() bool {
+func (x *ClusterProject) GetName() string {
 	if x != nil {
-		return x.Success
+		return x.Name
 	}
-	return false
+	return ""
+}
+
+func (x *ClusterProject) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
 }
 
-type RuleIndex struct {
+type ClusterMember struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Index int32 `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	UserID           string `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"` // exactly one of UserID, UserPrincipalID and GroupPrincipalID is set
+	UserPrincipalID  string `protobuf:"bytes,2,opt,name=UserPrincipalID,proto3" json:"UserPrincipalID,omitempty"`
+	GroupPrincipalID string `protobuf:"bytes,3,opt,name=GroupPrincipalID,proto3" json:"GroupPrincipalID,omitempty"`
+	Role             string `protobuf:"bytes,4,opt,name=Role,proto3" json:"Role,omitempty"` // cluster role template id, e.g. cluster-member
 }
 
-func (x *RuleIndex) Reset() {
-	*x = RuleIndex{}
+func (x *ClusterMember) Reset() {
+	*x = ClusterMember{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_moo_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -896,13 +1305,13 @@ func (x *RuleIndex) Reset() {
 	}
 }
 
-func (x *RuleIndex) String() string {
+func (x *ClusterMember) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RuleIndex) ProtoMessage() {}
+func (*ClusterMember) ProtoMessage() {}
 
-func (x *RuleIndex) ProtoReflect() protoreflect.Message {
+func (x *ClusterMember) ProtoReflect() protoreflect.Message {
 	mi := &file_moo_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -914,693 +1323,2911 @@ func (x *RuleIndex) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RuleIndex.ProtoReflect.Descriptor instead.
-func (*RuleIndex) Descriptor() ([]byte, []int) {
+// Deprecated: Use ClusterMember.ProtoReflect.Descriptor instead.
+func (*ClusterMember) Descriptor() ([]byte, []int) {
 	return file_moo_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *RuleIndex) GetIndex() int32 {
+func (x *ClusterMember) GetUserID() string {
 	if x != nil {
-		return x.Index
+		return x.UserID
 	}
-	return 0
+	return ""
 }
 
-var File_moo_proto protoreflect.FileDescriptor
+func (x *ClusterMember) GetUserPrincipalID() string {
+	if x != nil {
+		return x.UserPrincipalID
+	}
+	return ""
+}
 
-var file_moo_proto_rawDesc = []byte{
-	0x0a, 0x09, 0x6d, 0x6f, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x33, 0x0a, 0x11, 0x41,
-	0x67, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x1e, 0x0a, 0x06, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73,
-	0x22, 0x2e, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x19, 0x0a, 0x07, 0x41, 0x67, 0x65,
-	0x6e, 0x74, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x49, 0x44, 0x22, 0xa7, 0x01, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x6f, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x48, 0x6f, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x20,
-	0x0a, 0x0b, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0b, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65,
-	0x12, 0x1c, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xac,
-	0x02, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72,
-	0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x49, 0x50, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x50,
-	0x12, 0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x55, 0x72, 0x6c,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74,
-	0x55, 0x72, 0x6c, 0x12, 0x24, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d,
-	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f,
-	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x4c, 0x61, 0x73, 0x74, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4c, 0x61,
-	0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x55,
-	0x73, 0x65, 0x45, 0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0b, 0x55, 0x73, 0x65, 0x45, 0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x2c, 0x0a,
-	0x10, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x3e, 0x0a, 0x10, 0x4d,
-	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x55, 0x52, 0x4c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x55, 0x52, 0x4c, 0x22, 0x7c, 0x0a, 0x04, 0x52,
-	0x75, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x09, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x72, 0x69, 0x6f, 0x72,
-	0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x50, 0x72, 0x69, 0x6f, 0x72,
-	0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78, 0x22, 0x27, 0x0a, 0x08, 0x52, 0x75, 0x6c,
-	0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x05, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x52, 0x75, 0x6c,
-	0x65, 0x73, 0x22, 0x27, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x2a, 0x0a, 0x0e, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
-	0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
-	0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x21, 0x0a, 0x09, 0x52, 0x75, 0x6c, 0x65, 0x49,
-	0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x2a, 0x51, 0x0a, 0x06, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10,
-	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12,
-	0x08, 0x0a, 0x04, 0x48, 0x65, 0x6c, 0x64, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x65, 0x6e,
-	0x69, 0x65, 0x64, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
-	0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x05, 0x2a, 0x44, 0x0a,
-	0x08, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x49, 0x50, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x68, 0x61, 0x72, 0x65,
-	0x64, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x6c,
-	0x6c, 0x10, 0x03, 0x2a, 0x2c, 0x0a, 0x0a, 0x52, 0x75, 0x6c, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x6f, 0x6c, 0x64, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41,
-	0x63, 0x63, 0x65, 0x70, 0x74, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x65, 0x6e, 0x79, 0x10,
-	0x02, 0x32, 0xc5, 0x01, 0x0a, 0x03, 0x4d, 0x6f, 0x6f, 0x12, 0x2d, 0x0a, 0x0e, 0x47, 0x65, 0x74,
-	0x41, 0x67, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x08, 0x2e, 0x41, 0x67,
-	0x65, 0x6e, 0x74, 0x49, 0x44, 0x1a, 0x0f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0d, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e,
-	0x74, 0x1a, 0x11, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e,
-	0x69, 0x66, 0x65, 0x73, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x08, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74,
-	0x49, 0x44, 0x1a, 0x11, 0x2e, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41,
-	0x67, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x0c, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x78, 0x0a, 0x05, 0x52, 0x75, 0x6c,
-	0x65, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12,
-	0x06, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x09, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x4c, 0x69,
-	0x73, 0x74, 0x22, 0x00, 0x12, 0x20, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x52, 0x75, 0x6c, 0x65, 0x12,
-	0x05, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x1a, 0x0c, 0x2e, 0x41, 0x64, 0x64, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2b, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x52, 0x75, 0x6c, 0x65, 0x12, 0x0a, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78,
-	0x1a, 0x0f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x65, 0x62, 0x61, 0x75, 0x6d, 0x61, 0x6e, 0x2f, 0x6d, 0x6f, 0x6f, 0x2f, 0x70, 0x6b,
-	0x67, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ClusterMember) GetGroupPrincipalID() string {
+	if x != nil {
+		return x.GroupPrincipalID
+	}
+	return ""
 }
 
-var (
-	file_moo_proto_rawDescOnce sync.Once
-	file_moo_proto_rawDescData = file_moo_proto_rawDesc
-)
+func (x *ClusterMember) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
 
-func file_moo_proto_rawDescGZIP() []byte {
-	file_moo_proto_rawDescOnce.Do(func() {
-		file_moo_proto_rawDescData = protoimpl.X.CompressGZIP(file_moo_proto_rawDescData)
-	})
-	return file_moo_proto_rawDescData
+type Window struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Schedule string `protobuf:"bytes,1,opt,name=Schedule,proto3" json:"Schedule,omitempty"` // five field cron expression
+	Duration string `protobuf:"bytes,2,opt,name=Duration,proto3" json:"Duration,omitempty"` // how long the window stays open, e.g. 4h
 }
 
-var file_moo_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_moo_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
-var file_moo_proto_goTypes = []interface{}{
-	(Status)(0),               // 0: Status
-	(RuleType)(0),             // 1: RuleType
-	(RuleAction)(0),           // 2: RuleAction
-	(*AgentListResponse)(nil), // 3: AgentListResponse
-	(*ListRequest)(nil),       // 4: ListRequest
-	(*Empty)(nil),             // 5: Empty
-	(*AgentID)(nil),           // 6: AgentID
-	(*StatusResponse)(nil),    // 7: StatusResponse
-	(*Agent)(nil),             // 8: Agent
-	(*RegisterResponse)(nil),  // 9: RegisterResponse
-	(*ManifestResponse)(nil),  // 10: ManifestResponse
-	(*Rule)(nil),              // 11: Rule
-	(*RuleList)(nil),          // 12: RuleList
-	(*AddResponse)(nil),       // 13: AddResponse
-	(*DeleteResponse)(nil),    // 14: DeleteResponse
-	(*RuleIndex)(nil),         // 15: RuleIndex
+func (x *Window) Reset() {
+	*x = Window{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_moo_proto_depIdxs = []int32{
-	8,  // 0: AgentListResponse.Agents:type_name -> Agent
-	0,  // 1: ListRequest.Status:type_name -> Status
-	0,  // 2: StatusResponse.Status:type_name -> Status
-	0,  // 3: Agent.Status:type_name -> Status
-	1,  // 4: Rule.Type:type_name -> RuleType
-	2,  // 5: Rule.Action:type_name -> RuleAction
-	11, // 6: RuleList.Rules:type_name -> Rule
-	6,  // 7: Moo.GetAgentStatus:input_type -> AgentID
-	8,  // 8: Moo.RegisterAgent:input_type -> Agent
-	6,  // 9: Moo.GetManifestURL:input_type -> AgentID
-	4,  // 10: Moo.ListAgents:input_type -> ListRequest
-	5,  // 11: Rules.ListRules:input_type -> Empty
-	11, // 12: Rules.AddRule:input_type -> Rule
-	15, // 13: Rules.DeleteRule:input_type -> RuleIndex
-	7,  // 14: Moo.GetAgentStatus:output_type -> StatusResponse
-	9,  // 15: Moo.RegisterAgent:output_type -> RegisterResponse
-	10, // 16: Moo.GetManifestURL:output_type -> ManifestResponse
-	3,  // 17: Moo.ListAgents:output_type -> AgentListResponse
-	12, // 18: Rules.ListRules:output_type -> RuleList
-	13, // 19: Rules.AddRule:output_type -> AddResponse
-	14, // 20: Rules.DeleteRule:output_type -> DeleteResponse
-	14, // [14:21] is the sub-list for method output_type
-	7,  // [7:14] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+
+func (x *Window) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_moo_proto_init() }
-func file_moo_proto_init() {
-	if File_moo_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_moo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AgentListResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Empty); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*Window) ProtoMessage() {}
+
+func (x *Window) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_moo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AgentID); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Window.ProtoReflect.Descriptor instead.
+func (*Window) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Window) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Window) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+type Condition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      RuleType `protobuf:"varint,1,opt,name=Type,proto3,enum=RuleType" json:"Type,omitempty"` // any type but Compound
+	Regex     string   `protobuf:"bytes,2,opt,name=Regex,proto3" json:"Regex,omitempty"`
+	SecretIDs []string `protobuf:"bytes,3,rep,name=SecretIDs,proto3" json:"SecretIDs,omitempty"`
+	CIDRs     []string `protobuf:"bytes,4,rep,name=CIDRs,proto3" json:"CIDRs,omitempty"`
+	Not       bool     `protobuf:"varint,5,opt,name=Not,proto3" json:"Not,omitempty"` // invert the match
+	Selector  string   `protobuf:"bytes,6,opt,name=Selector,proto3" json:"Selector,omitempty"`
+}
+
+func (x *Condition) Reset() {
+	*x = Condition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Condition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Condition) ProtoMessage() {}
+
+func (x *Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_moo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatusResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Agent); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RegisterResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ManifestResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Rule); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_moo_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuleList); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Condition) GetType() RuleType {
+	if x != nil {
+		return x.Type
+	}
+	return RuleType_SourceIP
+}
+
+func (x *Condition) GetRegex() string {
+	if x != nil {
+		return x.Regex
+	}
+	return ""
+}
+
+func (x *Condition) GetSecretIDs() []string {
+	if x != nil {
+		return x.SecretIDs
+	}
+	return nil
+}
+
+func (x *Condition) GetCIDRs() []string {
+	if x != nil {
+		return x.CIDRs
+	}
+	return nil
+}
+
+func (x *Condition) GetNot() bool {
+	if x != nil {
+		return x.Not
+	}
+	return false
+}
+
+func (x *Condition) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+type RuleList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules []*Rule `protobuf:"bytes,1,rep,name=Rules,proto3" json:"Rules,omitempty"`
+}
+
+func (x *RuleList) Reset() {
+	*x = RuleList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuleList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleList) ProtoMessage() {}
+
+func (x *RuleList) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_moo_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleList.ProtoReflect.Descriptor instead.
+func (*RuleList) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RuleList) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// an existing agent, a hypothetical one, or an existing one with fields overridden
+type EvaluateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AgentID     string            `protobuf:"bytes,1,opt,name=AgentID,proto3" json:"AgentID,omitempty"`
+	ClusterName string            `protobuf:"bytes,2,opt,name=ClusterName,proto3" json:"ClusterName,omitempty"`
+	IP          string            `protobuf:"bytes,3,opt,name=IP,proto3" json:"IP,omitempty"`
+	Secret      string            `protobuf:"bytes,4,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	At          string            `protobuf:"bytes,5,opt,name=At,proto3" json:"At,omitempty"`                                                                                                 // RFC 3339, evaluate as of this time instead of now
+	Labels      map[string]string `protobuf:"bytes,6,rep,name=Labels,proto3" json:"Labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // replace the agent's labels
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_moo_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EvaluateRequest) GetAgentID() string {
+	if x != nil {
+		return x.AgentID
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetClusterName() string {
+	if x != nil {
+		return x.ClusterName
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetIP() string {
+	if x != nil {
+		return x.IP
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetAt() string {
+	if x != nil {
+		return x.At
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type RuleResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rule       *Rule  `protobuf:"bytes,1,opt,name=Rule,proto3" json:"Rule,omitempty"`
+	Matched    bool   `protobuf:"varint,2,opt,name=Matched,proto3" json:"Matched,omitempty"`
+	Conditions []bool `protobuf:"varint,3,rep,packed,name=Conditions,proto3" json:"Conditions,omitempty"` // result of each condition, with Not applied
+	Error      string `protobuf:"bytes,4,opt,name=Error,proto3" json:"Error,omitempty"`                   // set when the rule is invalid and was skipped
+	Active     bool   `protobuf:"varint,5,opt,name=Active,proto3" json:"Active,omitempty"`                // inactive rules are skipped
+	OverQuota  bool   `protobuf:"varint,6,opt,name=OverQuota,proto3" json:"OverQuota,omitempty"`          // the rule matched but has used up its matches
+}
+
+func (x *RuleResult) Reset() {
+	*x = RuleResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuleResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleResult) ProtoMessage() {}
+
+func (x *RuleResult) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_moo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuleIndex); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleResult.ProtoReflect.Descriptor instead.
+func (*RuleResult) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RuleResult) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *RuleResult) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *RuleResult) GetConditions() []bool {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *RuleResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RuleResult) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *RuleResult) GetOverQuota() bool {
+	if x != nil {
+		return x.OverQuota
+	}
+	return false
+}
+
+type EvaluateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*RuleResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"` // every rule, in evaluation order
+	RuleID  string        `protobuf:"bytes,2,opt,name=RuleID,proto3" json:"RuleID,omitempty"`   // the rule that decided, if any
+	Status  Status        `protobuf:"varint,3,opt,name=Status,proto3,enum=Status" json:"Status,omitempty"`
+	Message string        `protobuf:"bytes,4,opt,name=Message,proto3" json:"Message,omitempty"`
+}
+
+func (x *EvaluateResponse) Reset() {
+	*x = EvaluateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateResponse) ProtoMessage() {}
+
+func (x *EvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EvaluateResponse) GetResults() []*RuleResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *EvaluateResponse) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *EvaluateResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_Unknown
+}
+
+func (x *EvaluateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type AddResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success bool   `protobuf:"varint,1,opt,name=Success,proto3" json:"Success,omitempty"`
+	ID      string `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (x *AddResponse) Reset() {
+	*x = AddResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddResponse) ProtoMessage() {}
+
+func (x *AddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddResponse.ProtoReflect.Descriptor instead.
+func (*AddResponse) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddResponse) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success bool `protobuf:"varint,1,opt,name=Success,proto3" json:"Success,omitempty"`
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RuleID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID              string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	ResourceVersion int64  `protobuf:"varint,2,opt,name=ResourceVersion,proto3" json:"ResourceVersion,omitempty"` // when set, only delete the rule at this version
+}
+
+func (x *RuleID) Reset() {
+	*x = RuleID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuleID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleID) ProtoMessage() {}
+
+func (x *RuleID) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleID.ProtoReflect.Descriptor instead.
+func (*RuleID) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RuleID) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *RuleID) GetResourceVersion() int64 {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return 0
+}
+
+type TokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Role        Role   `protobuf:"varint,1,opt,name=Role,proto3,enum=Role" json:"Role,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=Description,proto3" json:"Description,omitempty"`
+}
+
+func (x *TokenRequest) Reset() {
+	*x = TokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenRequest) ProtoMessage() {}
+
+func (x *TokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenRequest.ProtoReflect.Descriptor instead.
+func (*TokenRequest) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TokenRequest) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_AgentRole
+}
+
+func (x *TokenRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Token struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID          string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Role        Role   `protobuf:"varint,2,opt,name=Role,proto3,enum=Role" json:"Role,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=Description,proto3" json:"Description,omitempty"`
+	Created     string `protobuf:"bytes,4,opt,name=Created,proto3" json:"Created,omitempty"`
+	Secret      string `protobuf:"bytes,5,opt,name=Secret,proto3" json:"Secret,omitempty"` // full bearer token, only returned by CreateToken
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Token) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Token) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_AgentRole
+}
+
+func (x *Token) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Token) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *Token) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type TokenList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tokens []*Token `protobuf:"bytes,1,rep,name=Tokens,proto3" json:"Tokens,omitempty"`
+}
+
+func (x *TokenList) Reset() {
+	*x = TokenList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenList) ProtoMessage() {}
+
+func (x *TokenList) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenList.ProtoReflect.Descriptor instead.
+func (*TokenList) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TokenList) GetTokens() []*Token {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type TokenID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (x *TokenID) Reset() {
+	*x = TokenID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenID) ProtoMessage() {}
+
+func (x *TokenID) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenID.ProtoReflect.Descriptor instead.
+func (*TokenID) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TokenID) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+type SecretRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Description string `protobuf:"bytes,1,opt,name=Description,proto3" json:"Description,omitempty"`
+}
+
+func (x *SecretRequest) Reset() {
+	*x = SecretRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretRequest) ProtoMessage() {}
+
+func (x *SecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretRequest.ProtoReflect.Descriptor instead.
+func (*SecretRequest) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SecretRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Secret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID          string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=Description,proto3" json:"Description,omitempty"`
+	Created     string `protobuf:"bytes,3,opt,name=Created,proto3" json:"Created,omitempty"`
+	Secret      string `protobuf:"bytes,4,opt,name=Secret,proto3" json:"Secret,omitempty"` // secret value, only returned by CreateSecret
+}
+
+func (x *Secret) Reset() {
+	*x = Secret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Secret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Secret) ProtoMessage() {}
+
+func (x *Secret) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
+func (*Secret) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Secret) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Secret) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Secret) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *Secret) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type SecretList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Secrets []*Secret `protobuf:"bytes,1,rep,name=Secrets,proto3" json:"Secrets,omitempty"`
+}
+
+func (x *SecretList) Reset() {
+	*x = SecretList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretList) ProtoMessage() {}
+
+func (x *SecretList) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretList.ProtoReflect.Descriptor instead.
+func (*SecretList) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SecretList) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+type SecretID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (x *SecretID) Reset() {
+	*x = SecretID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_moo_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretID) ProtoMessage() {}
+
+func (x *SecretID) ProtoReflect() protoreflect.Message {
+	mi := &file_moo_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretID.ProtoReflect.Descriptor instead.
+func (*SecretID) Descriptor() ([]byte, []int) {
+	return file_moo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SecretID) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+var File_moo_proto protoreflect.FileDescriptor
+
+var file_moo_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x6d, 0x6f, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x33, 0x0a, 0x11, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1e, 0x0a, 0x06, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73,
+	0x22, 0x2e, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x19, 0x0a, 0x07, 0x41, 0x67, 0x65,
+	0x6e, 0x74, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x49, 0x44, 0x22, 0x5b, 0x0a, 0x0b, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x41,
+	0x6c, 0x6c, 0x6f, 0x77, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0xc9, 0x01, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x48, 0x6f, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x48, 0x6f, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x50,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4d,
+	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x55, 0x52, 0x4c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x55, 0x52, 0x4c, 0x22, 0x9b, 0x05,
+	0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x50, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x50, 0x12,
+	0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x55, 0x72, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x55,
+	0x72, 0x6c, 0x12, 0x24, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x4c, 0x61, 0x73, 0x74, 0x43, 0x6f,
+	0x6e, 0x74, 0x61, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4c, 0x61, 0x73,
+	0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x55, 0x73,
+	0x65, 0x45, 0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x55, 0x73, 0x65, 0x45, 0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x44, 0x65, 0x63, 0x69,
+	0x64, 0x65, 0x64, 0x42, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x44, 0x65, 0x63,
+	0x69, 0x64, 0x65, 0x64, 0x42, 0x79, 0x12, 0x2a, 0x0a, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x07, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x07, 0x43,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x10, 0x52, 0x61, 0x6e, 0x63, 0x68, 0x65,
+	0x72, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x52, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x49, 0x44, 0x12, 0x2e, 0x0a, 0x12, 0x52, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12,
+	0x52, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x41,
+	0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x79,
+	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x2c, 0x0a, 0x10, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x3e, 0x0a, 0x10, 0x4d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x55, 0x52, 0x4c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x55, 0x52, 0x4c, 0x22, 0xda, 0x05, 0x0a, 0x04, 0x52, 0x75,
+	0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x09, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x23, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x0b, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x49, 0x44, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x49, 0x44, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x49, 0x44, 0x52, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x43, 0x49, 0x44, 0x52, 0x73, 0x12, 0x25, 0x0a, 0x08,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09,
+	0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12,
+	0x28, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x6f, 0x74,
+	0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e, 0x6f,
+	0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x07, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x07, 0x57,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f,
+	0x6e, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f,
+	0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x4d, 0x61,
+	0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x4d, 0x61, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x61, 0x74, 0x65,
+	0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x52, 0x61,
+	0x74, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x48, 0x6f, 0x6c, 0x64,
+	0x4f, 0x76, 0x65, 0x72, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0d, 0x48, 0x6f, 0x6c, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x18,
+	0x0a, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x52, 0x65, 0x63, 0x65,
+	0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x07, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x07, 0x43,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x22, 0xa2, 0x04, 0x0a, 0x0f, 0x43, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x06,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x43,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x12, 0x43, 0x0a, 0x0b, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x41, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x15, 0x45, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x38, 0x0a,
+	0x17, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f,
+	0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17,
+	0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x6e,
+	0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x30, 0x0a, 0x13, 0x45, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x2b, 0x0a, 0x08, 0x50, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x08, 0x50, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x07, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x07, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x41,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x46, 0x0a, 0x0e, 0x43,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x91, 0x01, 0x0a, 0x0d, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x28, 0x0a,
+	0x0f, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x69, 0x6e,
+	0x63, 0x69, 0x70, 0x61, 0x6c, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61,
+	0x6c, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x22, 0x40, 0x0a, 0x06, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xa2, 0x01, 0x0a, 0x09, 0x43, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x09, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x1c, 0x0a, 0x09,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x49, 0x44, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x49,
+	0x44, 0x52, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x43, 0x49, 0x44, 0x52, 0x73,
+	0x12, 0x10, 0x0a, 0x03, 0x4e, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x4e,
+	0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x27,
+	0x0a, 0x08, 0x52, 0x75, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x05, 0x52, 0x75,
+	0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x52, 0x75, 0x6c, 0x65,
+	0x52, 0x05, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xf6, 0x01, 0x0a, 0x0f, 0x45, 0x76, 0x61, 0x6c,
+	0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x50, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x50, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x41, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x41, 0x74, 0x12,
+	0x34, 0x0a, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xad, 0x01, 0x0a, 0x0a, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x19, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e,
+	0x52, 0x75, 0x6c, 0x65, 0x52, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x08, 0x52, 0x0a, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x76, 0x65, 0x72, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x4f, 0x76, 0x65, 0x72, 0x51, 0x75, 0x6f, 0x74, 0x61,
+	0x22, 0x8c, 0x01, 0x0a, 0x10, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x52, 0x75, 0x6c, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x52, 0x75,
+	0x6c, 0x65, 0x49, 0x44, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x07, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x37, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x22, 0x2a, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x53, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x22, 0x42, 0x0a, 0x06, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44, 0x12, 0x0e,
+	0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x28,
+	0x0a, 0x0f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4b, 0x0a, 0x0c, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x05, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x52,
+	0x6f, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x86, 0x01, 0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12,
+	0x19, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x05, 0x2e,
+	0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x2b,
+	0x0a, 0x09, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x06, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x52, 0x06, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x22, 0x19, 0x0a, 0x07, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x22, 0x31, 0x0a, 0x0d, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6c, 0x0a, 0x06, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x2f, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x07, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52,
+	0x07, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x22, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x49, 0x44, 0x2a, 0x61, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b,
+	0x0a, 0x07, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x65, 0x6c,
+	0x64, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x10, 0x03, 0x12,
+	0x0b, 0x0a, 0x07, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x05, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x65, 0x64, 0x10, 0x06, 0x2a, 0x5e, 0x0a, 0x08, 0x52, 0x75, 0x6c, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x50, 0x10,
+	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61,
+	0x6d, 0x65, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x6c, 0x6c, 0x10, 0x03, 0x12, 0x0c, 0x0a,
+	0x08, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x10, 0x05, 0x2a, 0x2c, 0x0a, 0x0a, 0x52, 0x75, 0x6c, 0x65, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x6f, 0x6c, 0x64, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x44,
+	0x65, 0x6e, 0x79, 0x10, 0x02, 0x2a, 0x1b, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x6e, 0x64, 0x10, 0x00, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x72,
+	0x10, 0x01, 0x2a, 0x24, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x10, 0x01, 0x32, 0xc2, 0x03, 0x0a, 0x03, 0x4d, 0x6f, 0x6f,
+	0x12, 0x2d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x08, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x1a, 0x0f, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x2c, 0x0a, 0x0d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x12, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x1a, 0x11, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2f, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x55, 0x52, 0x4c, 0x12,
+	0x08, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x1a, 0x11, 0x2e, 0x4d, 0x61, 0x6e, 0x69,
+	0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x30,
+	0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x0c, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x41, 0x67, 0x65,
+	0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x31, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x08, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x1a, 0x0f,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x12, 0x0c, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x1a, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x26, 0x0a, 0x0c, 0x41,
+	0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x0c, 0x2e, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e,
+	0x74, 0x22, 0x00, 0x12, 0x23, 0x0a, 0x09, 0x44, 0x65, 0x6e, 0x79, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x12, 0x0c, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x06,
+	0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x23, 0x0a, 0x09, 0x48, 0x6f, 0x6c, 0x64,
+	0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x0c, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x1a, 0x06, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x2e, 0x0a,
+	0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x0c, 0x2e, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0f, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xe8, 0x01,
+	0x0a, 0x05, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x12, 0x06, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x09, 0x2e, 0x52,
+	0x75, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x1b, 0x0a, 0x07, 0x47, 0x65, 0x74,
+	0x52, 0x75, 0x6c, 0x65, 0x12, 0x07, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44, 0x1a, 0x05, 0x2e,
+	0x52, 0x75, 0x6c, 0x65, 0x22, 0x00, 0x12, 0x20, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x05, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x1a, 0x0c, 0x2e, 0x41, 0x64, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x1c, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x05, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x1a, 0x05, 0x2e,
+	0x52, 0x75, 0x6c, 0x65, 0x22, 0x00, 0x12, 0x28, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x52, 0x75, 0x6c, 0x65, 0x12, 0x07, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44, 0x1a, 0x0f, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x36, 0x0a, 0x0d, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x75, 0x6c, 0x65,
+	0x73, 0x12, 0x10, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x80, 0x01, 0x0a, 0x06, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x0d, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x06, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x00, 0x12, 0x22, 0x0a, 0x0a, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x06, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x0a, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12,
+	0x2a, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x08,
+	0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x44, 0x1a, 0x0f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x88, 0x01, 0x0a, 0x07,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x0e, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x07, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x22, 0x00, 0x12, 0x24, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x73, 0x12, 0x06, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0b, 0x2e, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x09, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x49, 0x44, 0x1a, 0x0f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x62, 0x61, 0x75, 0x6d, 0x61, 0x6e, 0x2f, 0x6d, 0x6f, 0x6f,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_moo_proto_rawDescOnce sync.Once
+	file_moo_proto_rawDescData = file_moo_proto_rawDesc
+)
+
+func file_moo_proto_rawDescGZIP() []byte {
+	file_moo_proto_rawDescOnce.Do(func() {
+		file_moo_proto_rawDescData = protoimpl.X.CompressGZIP(file_moo_proto_rawDescData)
+	})
+	return file_moo_proto_rawDescData
+}
+
+var file_moo_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_moo_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_moo_proto_goTypes = []interface{}{
+	(Status)(0),               // 0: Status
+	(RuleType)(0),             // 1: RuleType
+	(RuleAction)(0),           // 2: RuleAction
+	(Operator)(0),             // 3: Operator
+	(Role)(0),                 // 4: Role
+	(*AgentListResponse)(nil), // 5: AgentListResponse
+	(*ListRequest)(nil),       // 6: ListRequest
+	(*Empty)(nil),             // 7: Empty
+	(*AgentID)(nil),           // 8: AgentID
+	(*AgentAction)(nil),       // 9: AgentAction
+	(*StatusResponse)(nil),    // 10: StatusResponse
+	(*Agent)(nil),             // 11: Agent
+	(*RegisterResponse)(nil),  // 12: RegisterResponse
+	(*ManifestResponse)(nil),  // 13: ManifestResponse
+	(*Rule)(nil),              // 14: Rule
+	(*ClusterSettings)(nil),   // 15: ClusterSettings
+	(*ClusterProject)(nil),    // 16: ClusterProject
+	(*ClusterMember)(nil),     // 17: ClusterMember
+	(*Window)(nil),            // 18: Window
+	(*Condition)(nil),         // 19: Condition
+	(*RuleList)(nil),          // 20: RuleList
+	(*EvaluateRequest)(nil),   // 21: EvaluateRequest
+	(*RuleResult)(nil),        // 22: RuleResult
+	(*EvaluateResponse)(nil),  // 23: EvaluateResponse
+	(*AddResponse)(nil),       // 24: AddResponse
+	(*DeleteResponse)(nil),    // 25: DeleteResponse
+	(*RuleID)(nil),            // 26: RuleID
+	(*TokenRequest)(nil),      // 27: TokenRequest
+	(*Token)(nil),             // 28: Token
+	(*TokenList)(nil),         // 29: TokenList
+	(*TokenID)(nil),           // 30: TokenID
+	(*SecretRequest)(nil),     // 31: SecretRequest
+	(*Secret)(nil),            // 32: Secret
+	(*SecretList)(nil),        // 33: SecretList
+	(*SecretID)(nil),          // 34: SecretID
+	nil,                       // 35: Agent.LabelsEntry
+	nil,                       // 36: ClusterSettings.LabelsEntry
+	nil,                       // 37: ClusterSettings.AnnotationsEntry
+	nil,                       // 38: EvaluateRequest.LabelsEntry
+}
+var file_moo_proto_depIdxs = []int32{
+	11, // 0: AgentListResponse.Agents:type_name -> Agent
+	0,  // 1: ListRequest.Status:type_name -> Status
+	0,  // 2: StatusResponse.Status:type_name -> Status
+	0,  // 3: Agent.Status:type_name -> Status
+	35, // 4: Agent.Labels:type_name -> Agent.LabelsEntry
+	15, // 5: Agent.Cluster:type_name -> ClusterSettings
+	1,  // 6: Rule.Type:type_name -> RuleType
+	2,  // 7: Rule.Action:type_name -> RuleAction
+	3,  // 8: Rule.Operator:type_name -> Operator
+	19, // 9: Rule.Conditions:type_name -> Condition
+	18, // 10: Rule.Windows:type_name -> Window
+	15, // 11: Rule.Cluster:type_name -> ClusterSettings
+	36, // 12: ClusterSettings.Labels:type_name -> ClusterSettings.LabelsEntry
+	37, // 13: ClusterSettings.Annotations:type_name -> ClusterSettings.AnnotationsEntry
+	16, // 14: ClusterSettings.Projects:type_name -> ClusterProject
+	17, // 15: ClusterSettings.Members:type_name -> ClusterMember
+	1,  // 16: Condition.Type:type_name -> RuleType
+	14, // 17: RuleList.Rules:type_name -> Rule
+	38, // 18: EvaluateRequest.Labels:type_name -> EvaluateRequest.LabelsEntry
+	14, // 19: RuleResult.Rule:type_name -> Rule
+	22, // 20: EvaluateResponse.Results:type_name -> RuleResult
+	0,  // 21: EvaluateResponse.Status:type_name -> Status
+	4,  // 22: TokenRequest.Role:type_name -> Role
+	4,  // 23: Token.Role:type_name -> Role
+	28, // 24: TokenList.Tokens:type_name -> Token
+	32, // 25: SecretList.Secrets:type_name -> Secret
+	8,  // 26: Moo.GetAgentStatus:input_type -> AgentID
+	11, // 27: Moo.RegisterAgent:input_type -> Agent
+	8,  // 28: Moo.GetManifestURL:input_type -> AgentID
+	6,  // 29: Moo.ListAgents:input_type -> ListRequest
+	8,  // 30: Moo.WatchAgentStatus:input_type -> AgentID
+	9,  // 31: Moo.RequeueAgent:input_type -> AgentAction
+	9,  // 32: Moo.ApproveAgent:input_type -> AgentAction
+	9,  // 33: Moo.DenyAgent:input_type -> AgentAction
+	9,  // 34: Moo.HoldAgent:input_type -> AgentAction
+	9,  // 35: Moo.DeleteAgent:input_type -> AgentAction
+	7,  // 36: Rules.ListRules:input_type -> Empty
+	26, // 37: Rules.GetRule:input_type -> RuleID
+	14, // 38: Rules.AddRule:input_type -> Rule
+	14, // 39: Rules.UpdateRule:input_type -> Rule
+	26, // 40: Rules.DeleteRule:input_type -> RuleID
+	21, // 41: Rules.EvaluateRules:input_type -> EvaluateRequest
+	27, // 42: Tokens.CreateToken:input_type -> TokenRequest
+	7,  // 43: Tokens.ListTokens:input_type -> Empty
+	30, // 44: Tokens.DeleteToken:input_type -> TokenID
+	31, // 45: Secrets.CreateSecret:input_type -> SecretRequest
+	7,  // 46: Secrets.ListSecrets:input_type -> Empty
+	34, // 47: Secrets.DeleteSecret:input_type -> SecretID
+	10, // 48: Moo.GetAgentStatus:output_type -> StatusResponse
+	12, // 49: Moo.RegisterAgent:output_type -> RegisterResponse
+	13, // 50: Moo.GetManifestURL:output_type -> ManifestResponse
+	5,  // 51: Moo.ListAgents:output_type -> AgentListResponse
+	10, // 52: Moo.WatchAgentStatus:output_type -> StatusResponse
+	11, // 53: Moo.RequeueAgent:output_type -> Agent
+	11, // 54: Moo.ApproveAgent:output_type -> Agent
+	11, // 55: Moo.DenyAgent:output_type -> Agent
+	11, // 56: Moo.HoldAgent:output_type -> Agent
+	25, // 57: Moo.DeleteAgent:output_type -> DeleteResponse
+	20, // 58: Rules.ListRules:output_type -> RuleList
+	14, // 59: Rules.GetRule:output_type -> Rule
+	24, // 60: Rules.AddRule:output_type -> AddResponse
+	14, // 61: Rules.UpdateRule:output_type -> Rule
+	25, // 62: Rules.DeleteRule:output_type -> DeleteResponse
+	23, // 63: Rules.EvaluateRules:output_type -> EvaluateResponse
+	28, // 64: Tokens.CreateToken:output_type -> Token
+	29, // 65: Tokens.ListTokens:output_type -> TokenList
+	25, // 66: Tokens.DeleteToken:output_type -> DeleteResponse
+	32, // 67: Secrets.CreateSecret:output_type -> Secret
+	33, // 68: Secrets.ListSecrets:output_type -> SecretList
+	25, // 69: Secrets.DeleteSecret:output_type -> DeleteResponse
+	48, // [48:70] is the sub-list for method output_type
+	26, // [26:48] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_moo_proto_init() }
+func file_moo_proto_init() {
+	if File_moo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_moo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AgentListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AgentID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AgentAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Agent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ManifestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterProject); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterMember); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Window); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Condition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuleList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuleResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuleID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Token); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Secret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_moo_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_moo_proto_rawDesc,
+			NumEnums:      5,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_moo_proto_goTypes,
+		DependencyIndexes: file_moo_proto_depIdxs,
+		EnumInfos:         file_moo_proto_enumTypes,
+		MessageInfos:      file_moo_proto_msgTypes,
+	}.Build()
+	File_moo_proto = out.File
+	file_moo_proto_rawDesc = nil
+	file_moo_proto_goTypes = nil
+	file_moo_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// MooClient is the client API for Moo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MooClient interface {
+	GetAgentStatus(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*StatusResponse, error)
+	RegisterAgent(ctx context.Context, in *Agent, opts ...grpc.CallOption) (*RegisterResponse, error)
+	GetManifestURL(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*ManifestResponse, error)
+	ListAgents(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*AgentListResponse, error)
+	WatchAgentStatus(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (Moo_WatchAgentStatusClient, error)
+	RequeueAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error)
+	ApproveAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error)
+	DenyAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error)
+	HoldAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error)
+	DeleteAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*DeleteResponse, error)
+}
+
+type mooClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMooClient(cc grpc.ClientConnInterface) MooClient {
+	return &mooClient{cc}
+}
+
+func (c *mooClient) GetAgentStatus(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, "/Moo/GetAgentStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) RegisterAgent(ctx context.Context, in *Agent, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, "/Moo/RegisterAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) GetManifestURL(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*ManifestResponse, error) {
+	out := new(ManifestResponse)
+	err := c.cc.Invoke(ctx, "/Moo/GetManifestURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) ListAgents(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*AgentListResponse, error) {
+	out := new(AgentListResponse)
+	err := c.cc.Invoke(ctx, "/Moo/ListAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) WatchAgentStatus(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (Moo_WatchAgentStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Moo_serviceDesc.Streams[0], "/Moo/WatchAgentStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mooWatchAgentStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Moo_WatchAgentStatusClient interface {
+	Recv() (*StatusResponse, error)
+	grpc.ClientStream
+}
+
+type mooWatchAgentStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *mooWatchAgentStatusClient) Recv() (*StatusResponse, error) {
+	m := new(StatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mooClient) RequeueAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error) {
+	out := new(Agent)
+	err := c.cc.Invoke(ctx, "/Moo/RequeueAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) ApproveAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error) {
+	out := new(Agent)
+	err := c.cc.Invoke(ctx, "/Moo/ApproveAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) DenyAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error) {
+	out := new(Agent)
+	err := c.cc.Invoke(ctx, "/Moo/DenyAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) HoldAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*Agent, error) {
+	out := new(Agent)
+	err := c.cc.Invoke(ctx, "/Moo/HoldAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mooClient) DeleteAgent(ctx context.Context, in *AgentAction, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/Moo/DeleteAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MooServer is the server API for Moo service.
+type MooServer interface {
+	GetAgentStatus(context.Context, *AgentID) (*StatusResponse, error)
+	RegisterAgent(context.Context, *Agent) (*RegisterResponse, error)
+	GetManifestURL(context.Context, *AgentID) (*ManifestResponse, error)
+	ListAgents(context.Context, *ListRequest) (*AgentListResponse, error)
+	WatchAgentStatus(*AgentID, Moo_WatchAgentStatusServer) error
+	RequeueAgent(context.Context, *AgentAction) (*Agent, error)
+	ApproveAgent(context.Context, *AgentAction) (*Agent, error)
+	DenyAgent(context.Context, *AgentAction) (*Agent, error)
+	HoldAgent(context.Context, *AgentAction) (*Agent, error)
+	DeleteAgent(context.Context, *AgentAction) (*DeleteResponse, error)
+}
+
+// UnimplementedMooServer can be embedded to have forward compatible implementations.
+type UnimplementedMooServer struct {
+}
+
+func (*UnimplementedMooServer) GetAgentStatus(context.Context, *AgentID) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgentStatus not implemented")
+}
+func (*UnimplementedMooServer) RegisterAgent(context.Context, *Agent) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterAgent not implemented")
+}
+func (*UnimplementedMooServer) GetManifestURL(context.Context, *AgentID) (*ManifestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetManifestURL not implemented")
+}
+func (*UnimplementedMooServer) ListAgents(context.Context, *ListRequest) (*AgentListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgents not implemented")
+}
+func (*UnimplementedMooServer) WatchAgentStatus(*AgentID, Moo_WatchAgentStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAgentStatus not implemented")
+}
+func (*UnimplementedMooServer) RequeueAgent(context.Context, *AgentAction) (*Agent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequeueAgent not implemented")
+}
+func (*UnimplementedMooServer) ApproveAgent(context.Context, *AgentAction) (*Agent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAgent not implemented")
+}
+func (*UnimplementedMooServer) DenyAgent(context.Context, *AgentAction) (*Agent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyAgent not implemented")
+}
+func (*UnimplementedMooServer) HoldAgent(context.Context, *AgentAction) (*Agent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HoldAgent not implemented")
+}
+func (*UnimplementedMooServer) DeleteAgent(context.Context, *AgentAction) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAgent not implemented")
+}
+
+func RegisterMooServer(s *grpc.Server, srv MooServer) {
+	s.RegisterService(&_Moo_serviceDesc, srv)
+}
+
+func _Moo_GetAgentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).GetAgentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/GetAgentStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).GetAgentStatus(ctx, req.(*AgentID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_RegisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Agent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).RegisterAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/RegisterAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).RegisterAgent(ctx, req.(*Agent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_GetManifestURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).GetManifestURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/GetManifestURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).GetManifestURL(ctx, req.(*AgentID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/ListAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).ListAgents(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_WatchAgentStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AgentID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MooServer).WatchAgentStatus(m, &mooWatchAgentStatusServer{stream})
+}
+
+type Moo_WatchAgentStatusServer interface {
+	Send(*StatusResponse) error
+	grpc.ServerStream
+}
+
+type mooWatchAgentStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *mooWatchAgentStatusServer) Send(m *StatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Moo_RequeueAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).RequeueAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/RequeueAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).RequeueAgent(ctx, req.(*AgentAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_ApproveAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).ApproveAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/ApproveAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).ApproveAgent(ctx, req.(*AgentAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_DenyAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).DenyAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/DenyAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).DenyAgent(ctx, req.(*AgentAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_HoldAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).HoldAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/HoldAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).HoldAgent(ctx, req.(*AgentAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moo_DeleteAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentAction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MooServer).DeleteAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Moo/DeleteAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MooServer).DeleteAgent(ctx, req.(*AgentAction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Moo_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "Moo",
+	HandlerType: (*MooServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAgentStatus",
+			Handler:    _Moo_GetAgentStatus_Handler,
+		},
+		{
+			MethodName: "RegisterAgent",
+			Handler:    _Moo_RegisterAgent_Handler,
+		},
+		{
+			MethodName: "GetManifestURL",
+			Handler:    _Moo_GetManifestURL_Handler,
+		},
+		{
+			MethodName: "ListAgents",
+			Handler:    _Moo_ListAgents_Handler,
+		},
+		{
+			MethodName: "RequeueAgent",
+			Handler:    _Moo_RequeueAgent_Handler,
+		},
+		{
+			MethodName: "ApproveAgent",
+			Handler:    _Moo_ApproveAgent_Handler,
+		},
+		{
+			MethodName: "DenyAgent",
+			Handler:    _Moo_DenyAgent_Handler,
+		},
+		{
+			MethodName: "HoldAgent",
+			Handler:    _Moo_HoldAgent_Handler,
+		},
+		{
+			MethodName: "DeleteAgent",
+			Handler:    _Moo_DeleteAgent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAgentStatus",
+			Handler:       _Moo_WatchAgentStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "moo.proto",
+}
+
+// RulesClient is the client API for Rules service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RulesClient interface {
+	ListRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RuleList, error)
+	GetRule(ctx context.Context, in *RuleID, opts ...grpc.CallOption) (*Rule, error)
+	AddRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*AddResponse, error)
+	UpdateRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*Rule, error)
+	DeleteRule(ctx context.Context, in *RuleID, opts ...grpc.CallOption) (*DeleteResponse, error)
+	EvaluateRules(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+}
+
+type rulesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRulesClient(cc grpc.ClientConnInterface) RulesClient {
+	return &rulesClient{cc}
+}
+
+func (c *rulesClient) ListRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RuleList, error) {
+	out := new(RuleList)
+	err := c.cc.Invoke(ctx, "/Rules/ListRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) GetRule(ctx context.Context, in *RuleID, opts ...grpc.CallOption) (*Rule, error) {
+	out := new(Rule)
+	err := c.cc.Invoke(ctx, "/Rules/GetRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) AddRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*AddResponse, error) {
+	out := new(AddResponse)
+	err := c.cc.Invoke(ctx, "/Rules/AddRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) UpdateRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*Rule, error) {
+	out := new(Rule)
+	err := c.cc.Invoke(ctx, "/Rules/UpdateRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) DeleteRule(ctx context.Context, in *RuleID, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/Rules/DeleteRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) EvaluateRules(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/Rules/EvaluateRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RulesServer is the server API for Rules service.
+type RulesServer interface {
+	ListRules(context.Context, *Empty) (*RuleList, error)
+	GetRule(context.Context, *RuleID) (*Rule, error)
+	AddRule(context.Context, *Rule) (*AddResponse, error)
+	UpdateRule(context.Context, *Rule) (*Rule, error)
+	DeleteRule(context.Context, *RuleID) (*DeleteResponse, error)
+	EvaluateRules(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+}
+
+// UnimplementedRulesServer can be embedded to have forward compatible implementations.
+type UnimplementedRulesServer struct {
+}
+
+func (*UnimplementedRulesServer) ListRules(context.Context, *Empty) (*RuleList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRules not implemented")
+}
+func (*UnimplementedRulesServer) GetRule(context.Context, *RuleID) (*Rule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRule not implemented")
+}
+func (*UnimplementedRulesServer) AddRule(context.Context, *Rule) (*AddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRule not implemented")
+}
+func (*UnimplementedRulesServer) UpdateRule(context.Context, *Rule) (*Rule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRule not implemented")
+}
+func (*UnimplementedRulesServer) DeleteRule(context.Context, *RuleID) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRule not implemented")
+}
+func (*UnimplementedRulesServer) EvaluateRules(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateRules not implemented")
+}
+
+func RegisterRulesServer(s *grpc.Server, srv RulesServer) {
+	s.RegisterService(&_Rules_serviceDesc, srv)
+}
+
+func _Rules_ListRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).ListRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Rules/ListRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).ListRules(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rules_GetRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).GetRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Rules/GetRule",
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_moo_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   13,
-			NumExtensions: 0,
-			NumServices:   2,
-		},
-		GoTypes:           file_moo_proto_goTypes,
-		DependencyIndexes: file_moo_proto_depIdxs,
-		EnumInfos:         file_moo_proto_enumTypes,
-		MessageInfos:      file_moo_proto_msgTypes,
-	}.Build()
-	File_moo_proto = out.File
-	file_moo_proto_rawDesc = nil
-	file_moo_proto_goTypes = nil
-	file_moo_proto_depIdxs = nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).GetRule(ctx, req.(*RuleID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+func _Rules_AddRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Rule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).AddRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Rules/AddRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).AddRule(ctx, req.(*Rule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func _Rules_UpdateRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Rule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).UpdateRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Rules/UpdateRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).UpdateRule(ctx, req.(*Rule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// MooClient is the client API for Moo service.
+func _Rules_DeleteRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).DeleteRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Rules/DeleteRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).DeleteRule(ctx, req.(*RuleID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rules_EvaluateRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).EvaluateRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Rules/EvaluateRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).EvaluateRules(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Rules_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "Rules",
+	HandlerType: (*RulesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRules",
+			Handler:    _Rules_ListRules_Handler,
+		},
+		{
+			MethodName: "GetRule",
+			Handler:    _Rules_GetRule_Handler,
+		},
+		{
+			MethodName: "AddRule",
+			Handler:    _Rules_AddRule_Handler,
+		},
+		{
+			MethodName: "UpdateRule",
+			Handler:    _Rules_UpdateRule_Handler,
+		},
+		{
+			MethodName: "DeleteRule",
+			Handler:    _Rules_DeleteRule_Handler,
+		},
+		{
+			MethodName: "EvaluateRules",
+			Handler:    _Rules_EvaluateRules_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "moo.proto",
+}
+
+// TokensClient is the client API for Tokens service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MooClient interface {
-	GetAgentStatus(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*StatusResponse, error)
-	RegisterAgent(ctx context.Context, in *Agent, opts ...grpc.CallOption) (*RegisterResponse, error)
-	GetManifestURL(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*ManifestResponse, error)
-	ListAgents(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*AgentListResponse, error)
+type TokensClient interface {
+	CreateToken(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*Token, error)
+	ListTokens(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TokenList, error)
+	DeleteToken(ctx context.Context, in *TokenID, opts ...grpc.CallOption) (*DeleteResponse, error)
 }
 
-type mooClient struct {
+type tokensClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewMooClient(cc grpc.ClientConnInterface) MooClient {
-	return &mooClient{cc}
-}
-
-func (c *mooClient) GetAgentStatus(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*StatusResponse, error) {
-	out := new(StatusResponse)
-	err := c.cc.Invoke(ctx, "/Moo/GetAgentStatus", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func NewTokensClient(cc grpc.ClientConnInterface) TokensClient {
+	return &tokensClient{cc}
 }
 
-func (c *mooClient) RegisterAgent(ctx context.Context, in *Agent, opts ...grpc.CallOption) (*RegisterResponse, error) {
-	out := new(RegisterResponse)
-	err := c.cc.Invoke(ctx, "/Moo/RegisterAgent", in, out, opts...)
+func (c *tokensClient) CreateToken(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*Token, error) {
+	out := new(Token)
+	err := c.cc.Invoke(ctx, "/Tokens/CreateToken", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *mooClient) GetManifestURL(ctx context.Context, in *AgentID, opts ...grpc.CallOption) (*ManifestResponse, error) {
-	out := new(ManifestResponse)
-	err := c.cc.Invoke(ctx, "/Moo/GetManifestURL", in, out, opts...)
+func (c *tokensClient) ListTokens(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TokenList, error) {
+	out := new(TokenList)
+	err := c.cc.Invoke(ctx, "/Tokens/ListTokens", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *mooClient) ListAgents(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*AgentListResponse, error) {
-	out := new(AgentListResponse)
-	err := c.cc.Invoke(ctx, "/Moo/ListAgents", in, out, opts...)
+func (c *tokensClient) DeleteToken(ctx context.Context, in *TokenID, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/Tokens/DeleteToken", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// MooServer is the server API for Moo service.
-type MooServer interface {
-	GetAgentStatus(context.Context, *AgentID) (*StatusResponse, error)
-	RegisterAgent(context.Context, *Agent) (*RegisterResponse, error)
-	GetManifestURL(context.Context, *AgentID) (*ManifestResponse, error)
-	ListAgents(context.Context, *ListRequest) (*AgentListResponse, error)
+// TokensServer is the server API for Tokens service.
+type TokensServer interface {
+	CreateToken(context.Context, *TokenRequest) (*Token, error)
+	ListTokens(context.Context, *Empty) (*TokenList, error)
+	DeleteToken(context.Context, *TokenID) (*DeleteResponse, error)
 }
 
-// UnimplementedMooServer can be embedded to have forward compatible implementations.
-type UnimplementedMooServer struct {
+// UnimplementedTokensServer can be embedded to have forward compatible implementations.
+type UnimplementedTokensServer struct {
 }
 
-func (*UnimplementedMooServer) GetAgentStatus(context.Context, *AgentID) (*StatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetAgentStatus not implemented")
-}
-func (*UnimplementedMooServer) RegisterAgent(context.Context, *Agent) (*RegisterResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterAgent not implemented")
-}
-func (*UnimplementedMooServer) GetManifestURL(context.Context, *AgentID) (*ManifestResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetManifestURL not implemented")
+func (*UnimplementedTokensServer) CreateToken(context.Context, *TokenRequest) (*Token, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateToken not implemented")
 }
-func (*UnimplementedMooServer) ListAgents(context.Context, *ListRequest) (*AgentListResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListAgents not implemented")
+func (*UnimplementedTokensServer) ListTokens(context.Context, *Empty) (*TokenList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokens not implemented")
 }
-
-func RegisterMooServer(s *grpc.Server, srv MooServer) {
-	s.RegisterService(&_Moo_serviceDesc, srv)
+func (*UnimplementedTokensServer) DeleteToken(context.Context, *TokenID) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteToken not implemented")
 }
 
-func _Moo_GetAgentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AgentID)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MooServer).GetAgentStatus(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/Moo/GetAgentStatus",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MooServer).GetAgentStatus(ctx, req.(*AgentID))
-	}
-	return interceptor(ctx, in, info, handler)
+func RegisterTokensServer(s *grpc.Server, srv TokensServer) {
+	s.RegisterService(&_Tokens_serviceDesc, srv)
 }
 
-func _Moo_RegisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Agent)
+func _Tokens_CreateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MooServer).RegisterAgent(ctx, in)
+		return srv.(TokensServer).CreateToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/Moo/RegisterAgent",
+		FullMethod: "/Tokens/CreateToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MooServer).RegisterAgent(ctx, req.(*Agent))
+		return srv.(TokensServer).CreateToken(ctx, req.(*TokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Moo_GetManifestURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AgentID)
+func _Tokens_ListTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MooServer).GetManifestURL(ctx, in)
+		return srv.(TokensServer).ListTokens(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/Moo/GetManifestURL",
+		FullMethod: "/Tokens/ListTokens",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MooServer).GetManifestURL(ctx, req.(*AgentID))
+		return srv.(TokensServer).ListTokens(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Moo_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
+func _Tokens_DeleteToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenID)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MooServer).ListAgents(ctx, in)
+		return srv.(TokensServer).DeleteToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/Moo/ListAgents",
+		FullMethod: "/Tokens/DeleteToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MooServer).ListAgents(ctx, req.(*ListRequest))
+		return srv.(TokensServer).DeleteToken(ctx, req.(*TokenID))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Moo_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "Moo",
-	HandlerType: (*MooServer)(nil),
+var _Tokens_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "Tokens",
+	HandlerType: (*TokensServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "GetAgentStatus",
-			Handler:    _Moo_GetAgentStatus_Handler,
-		},
-		{
-			MethodName: "RegisterAgent",
-			Handler:    _Moo_RegisterAgent_Handler,
+			MethodName: "CreateToken",
+			Handler:    _Tokens_CreateToken_Handler,
 		},
 		{
-			MethodName: "GetManifestURL",
-			Handler:    _Moo_GetManifestURL_Handler,
+			MethodName: "ListTokens",
+			Handler:    _Tokens_ListTokens_Handler,
 		},
 		{
-			MethodName: "ListAgents",
-			Handler:    _Moo_ListAgents_Handler,
+			MethodName: "DeleteToken",
+			Handler:    _Tokens_DeleteToken_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "moo.proto",
 }
 
-// RulesClient is the client API for Rules service.
+// SecretsClient is the client API for Secrets service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type RulesClient interface {
-	ListRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RuleList, error)
-	AddRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*AddResponse, error)
-	DeleteRule(ctx context.Context, in *RuleIndex, opts ...grpc.CallOption) (*DeleteResponse, error)
+type SecretsClient interface {
+	CreateSecret(ctx context.Context, in *SecretRequest, opts ...grpc.CallOption) (*Secret, error)
+	ListSecrets(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SecretList, error)
+	DeleteSecret(ctx context.Context, in *SecretID, opts ...grpc.CallOption) (*DeleteResponse, error)
 }
 
-type rulesClient struct {
+type secretsClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewRulesClient(cc grpc.ClientConnInterface) RulesClient {
-	return &rulesClient{cc}
+func NewSecretsClient(cc grpc.ClientConnInterface) SecretsClient {
+	return &secretsClient{cc}
 }
 
-func (c *rulesClient) ListRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RuleList, error) {
-	out := new(RuleList)
-	err := c.cc.Invoke(ctx, "/Rules/ListRules", in, out, opts...)
+func (c *secretsClient) CreateSecret(ctx context.Context, in *SecretRequest, opts ...grpc.CallOption) (*Secret, error) {
+	out := new(Secret)
+	err := c.cc.Invoke(ctx, "/Secrets/CreateSecret", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *rulesClient) AddRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*AddResponse, error) {
-	out := new(AddResponse)
-	err := c.cc.Invoke(ctx, "/Rules/AddRule", in, out, opts...)
+func (c *secretsClient) ListSecrets(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SecretList, error) {
+	out := new(SecretList)
+	err := c.cc.Invoke(ctx, "/Secrets/ListSecrets", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *rulesClient) DeleteRule(ctx context.Context, in *RuleIndex, opts ...grpc.CallOption) (*DeleteResponse, error) {
+func (c *secretsClient) DeleteSecret(ctx context.Context, in *SecretID, opts ...grpc.CallOption) (*DeleteResponse, error) {
 	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/Rules/DeleteRule", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/Secrets/DeleteSecret", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// RulesServer is the server API for Rules service.
-type RulesServer interface {
-	ListRules(context.Context, *Empty) (*RuleList, error)
-	AddRule(context.Context, *Rule) (*AddResponse, error)
-	DeleteRule(context.Context, *RuleIndex) (*DeleteResponse, error)
+// SecretsServer is the server API for Secrets service.
+type SecretsServer interface {
+	CreateSecret(context.Context, *SecretRequest) (*Secret, error)
+	ListSecrets(context.Context, *Empty) (*SecretList, error)
+	DeleteSecret(context.Context, *SecretID) (*DeleteResponse, error)
 }
 
-// UnimplementedRulesServer can be embedded to have forward compatible implementations.
-type UnimplementedRulesServer struct {
+// UnimplementedSecretsServer can be embedded to have forward compatible implementations.
+type UnimplementedSecretsServer struct {
 }
 
-func (*UnimplementedRulesServer) ListRules(context.Context, *Empty) (*RuleList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListRules not implemented")
+func (*UnimplementedSecretsServer) CreateSecret(context.Context, *SecretRequest) (*Secret, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSecret not implemented")
 }
-func (*UnimplementedRulesServer) AddRule(context.Context, *Rule) (*AddResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddRule not implemented")
+func (*UnimplementedSecretsServer) ListSecrets(context.Context, *Empty) (*SecretList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
 }
-func (*UnimplementedRulesServer) DeleteRule(context.Context, *RuleIndex) (*DeleteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteRule not implemented")
+func (*UnimplementedSecretsServer) DeleteSecret(context.Context, *SecretID) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecret not implemented")
 }
 
-func RegisterRulesServer(s *grpc.Server, srv RulesServer) {
-	s.RegisterService(&_Rules_serviceDesc, srv)
+func RegisterSecretsServer(s *grpc.Server, srv SecretsServer) {
+	s.RegisterService(&_Secrets_serviceDesc, srv)
 }
 
-func _Rules_ListRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _Secrets_CreateSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SecretRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RulesServer).ListRules(ctx, in)
+		return srv.(SecretsServer).CreateSecret(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/Rules/ListRules",
+		FullMethod: "/Secrets/CreateSecret",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RulesServer).ListRules(ctx, req.(*Empty))
+		return srv.(SecretsServer).CreateSecret(ctx, req.(*SecretRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Rules_AddRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Rule)
+func _Secrets_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RulesServer).AddRule(ctx, in)
+		return srv.(SecretsServer).ListSecrets(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/Rules/AddRule",
+		FullMethod: "/Secrets/ListSecrets",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RulesServer).AddRule(ctx, req.(*Rule))
+		return srv.(SecretsServer).ListSecrets(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Rules_DeleteRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RuleIndex)
+func _Secrets_DeleteSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SecretID)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RulesServer).DeleteRule(ctx, in)
+		return srv.(SecretsServer).DeleteSecret(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/Rules/DeleteRule",
+		FullMethod: "/Secrets/DeleteSecret",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RulesServer).DeleteRule(ctx, req.(*RuleIndex))
+		return srv.(SecretsServer).DeleteSecret(ctx, req.(*SecretID))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Rules_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "Rules",
-	HandlerType: (*RulesServer)(nil),
+var _Secrets_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "Secrets",
+	HandlerType: (*SecretsServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "ListRules",
-			Handler:    _Rules_ListRules_Handler,
+			MethodName: "CreateSecret",
+			Handler:    _Secrets_CreateSecret_Handler,
 		},
 		{
-			MethodName: "AddRule",
-			Handler:    _Rules_AddRule_Handler,
+			MethodName: "ListSecrets",
+			Handler:    _Secrets_ListSecrets_Handler,
 		},
 		{
-			MethodName: "DeleteRule",
-			Handler:    _Rules_DeleteRule_Handler,
+			MethodName: "DeleteSecret",
+			Handler:    _Secrets_DeleteSecret_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,109 @@
+package rulestore
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	"strings"
+)
+
+// FieldError is a problem with one field of a rule
+type FieldError struct {
+	Field       string
+	Description string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Description)
+}
+
+type fieldErrors []FieldError
+
+func (e *fieldErrors) add(field string, format string, args ...interface{}) {
+	*e = append(*e, FieldError{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// CheckRule checks the action and type of a rule, and that the rule and each
+// of its conditions only set the fields their type uses. a rule that fails the
+// check never compiles, wherever it was stored from.
+func CheckRule(r types.Rule) []FieldError {
+	errs := fieldErrors{}
+
+	switch r.Action {
+	case types.Accept, types.Hold, types.Deny:
+	default:
+		errs.add("action", "unknown action %q", r.Action)
+	}
+
+	switch r.Type {
+	case types.Compound:
+		if r.Regex != "" || len(r.CIDRs) > 0 || len(r.SecretIDs) > 0 || r.Selector != "" {
+			errs.add("type", "%s rules match on their conditions only", types.Compound)
+		}
+		if len(r.Conditions) == 0 {
+			errs.add("conditions", "%s rules need at least one condition", types.Compound)
+		}
+		switch r.Operator {
+		case "", types.And, types.Or:
+		default:
+			errs.add("operator", "unknown operator %q", r.Operator)
+		}
+		for i, c := range r.Conditions {
+			checkCondition(c, fmt.Sprintf("conditions[%d].", i), &errs)
+		}
+	case types.SourceIP, types.SharedSecret, types.ClusterName, types.All, types.Labels:
+		if r.Operator != "" || len(r.Conditions) > 0 {
+			errs.add("conditions", "conditions are only valid on %s rules", types.Compound)
+		}
+		checkCondition(RuleConditions(r)[0], "", &errs)
+	default:
+		errs.add("type", "unknown rule type %q", r.Type)
+	}
+
+	return errs
+}
+
+func checkCondition(c types.Condition, prefix string, errs *fieldErrors) {
+	switch c.Type {
+	case types.SourceIP, types.SharedSecret, types.ClusterName, types.All, types.Labels:
+	case types.Compound:
+		errs.add(prefix+"type", "conditions cannot be nested")
+		return
+	default:
+		errs.add(prefix+"type", "unknown rule type %q", c.Type)
+		return
+	}
+
+	// a field the condition doesn't use would be silently ignored, e.g. a
+	// SharedSecret rule with a regex would match any secret
+	if c.Regex != "" && c.Type != types.ClusterName && c.Type != types.SourceIP {
+		errs.add(prefix+"regex", "regex is not used by %s rules", c.Type)
+	}
+
+	if c.Type == types.Labels && c.Selector == "" {
+		errs.add(prefix+"selector", "%s rules need a selector", types.Labels)
+	} else if c.Type != types.Labels && c.Selector != "" {
+		errs.add(prefix+"selector", "selectors are only valid on %s rules", types.Labels)
+	}
+
+	if len(c.SecretIDs) > 0 && c.Type != types.SharedSecret {
+		errs.add(prefix+"secretIDs", "secret ids are only valid on %s rules", types.SharedSecret)
+	}
+
+	if len(c.CIDRs) > 0 {
+		if c.Type != types.SourceIP {
+			errs.add(prefix+"cidrs", "cidrs are only valid on %s rules", types.SourceIP)
+		} else if c.Regex != "" {
+			errs.add(prefix+"cidrs", "a rule may have cidrs or a regex, not both")
+		}
+	}
+}
+
+// joinFieldErrors is the error Compile fails with
+func joinFieldErrors(errs []FieldError) error {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
@@ -0,0 +1,156 @@
+package rulestore
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/netutil"
+	"github.com/ebauman/moo/pkg/types"
+	"k8s.io/apimachinery/pkg/labels"
+	"net"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// CompiledRule is a rule with the regexes, cidr blocks and label selectors of
+// its conditions parsed ahead of evaluation, along with its windows, time zone
+// and rate period. a rule that failed to compile carries the error and should
+// never match.
+type CompiledRule struct {
+	types.Rule
+	Matchers      []Matcher
+	ParsedWindows []Window
+	Location      *time.Location
+	Period        time.Duration
+	Err           error
+}
+
+type Matcher struct {
+	types.Condition
+	Pattern       *regexp.Regexp
+	Nets          []*net.IPNet
+	LabelSelector labels.Selector
+}
+
+// RuleConditions returns the conditions a rule matches on. a rule that isn't
+// Compound is a single condition.
+func RuleConditions(r types.Rule) []types.Condition {
+	if r.Type == types.Compound {
+		return r.Conditions
+	}
+
+	return []types.Condition{{
+		Type:      r.Type,
+		Regex:     r.Regex,
+		SecretIDs: r.SecretIDs,
+		CIDRs:     r.CIDRs,
+		Selector:  r.Selector,
+	}}
+}
+
+// Compile checks a rule and parses the regexes, cidr blocks, label selectors,
+// windows and rate period of it
+func Compile(r types.Rule) (CompiledRule, error) {
+	compiled := CompiledRule{Rule: r}
+
+	if errs := CheckRule(r); len(errs) > 0 {
+		return compiled, joinFieldErrors(errs)
+	}
+
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return compiled, fmt.Errorf("invalid time zone %q", r.TimeZone)
+	}
+	compiled.Location = loc
+
+	for i, w := range r.Windows {
+		parsed, err := ParseWindow(w)
+		if err != nil {
+			return compiled, fmt.Errorf("window %d: %v", i, err)
+		}
+		compiled.ParsedWindows = append(compiled.ParsedWindows, parsed)
+	}
+
+	if r.RateLimit > 0 {
+		period, err := time.ParseDuration(r.RatePeriod)
+		if err != nil || period <= 0 {
+			return compiled, fmt.Errorf("invalid rate period %q", r.RatePeriod)
+		}
+		compiled.Period = period
+	}
+
+	for i, c := range RuleConditions(r) {
+		m := Matcher{Condition: c}
+
+		if len(c.CIDRs) == 0 {
+			regex, err := regexp.Compile(c.Regex)
+			if err != nil {
+				return compiled, fmt.Errorf("condition %d: invalid regex: %v", i, err)
+			}
+			m.Pattern = regex
+		}
+
+		if c.Type == types.Labels {
+			selector, err := labels.Parse(c.Selector)
+			if err != nil {
+				return compiled, fmt.Errorf("condition %d: invalid selector: %v", i, err)
+			}
+			m.LabelSelector = selector
+		}
+
+		for _, v := range c.CIDRs {
+			n, err := netutil.ParseCIDR(v)
+			if err != nil {
+				return compiled, fmt.Errorf("condition %d: %v", i, err)
+			}
+			m.Nets = append(m.Nets, n)
+		}
+
+		compiled.Matchers = append(compiled.Matchers, m)
+	}
+
+	return compiled, nil
+}
+
+// CompiledRules returns the rules in evaluation order, compiled. rules are
+// only recompiled when they have changed in the backend, which may happen
+// outside of this store (e.g. rules edited with kubectl). invalid rules are
+// logged as they are compiled rather than each time they are skipped. the
+// usage of a rule changes with every agent it decides but doesn't affect
+// compiling, so it is left out of the comparison and taken from the rules as
+// they are now.
+func (s *Store) CompiledRules() []CompiledRule {
+	rules := s.ListRules()
+
+	specs := make([]types.Rule, len(rules))
+	for i, r := range rules {
+		r.Usage = types.RuleUsage{}
+		specs[i] = r
+	}
+
+	s.compiledMu.Lock()
+	defer s.compiledMu.Unlock()
+
+	if s.compiled == nil || !reflect.DeepEqual(specs, s.compiledFrom) {
+		compiled := make([]CompiledRule, len(rules))
+		for i, r := range rules {
+			c, err := Compile(r)
+			if err != nil {
+				s.log.Warnf("rule %s (type: %s) is invalid and will be skipped: %v", r.ID, r.Type, err)
+			}
+			c.Err = err
+			compiled[i] = c
+		}
+		s.compiled = compiled
+		s.compiledFrom = specs
+	}
+
+	// the compiled rules are never modified once built, but the slice is
+	// copied so callers can't reorder ours
+	out := make([]CompiledRule, len(s.compiled))
+	copy(out, s.compiled)
+	for i := range out {
+		out[i].Usage = rules[i].Usage
+	}
+
+	return out
+}
@@ -0,0 +1,97 @@
+package rulestore
+
+import (
+	"bytes"
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileCIDRs(t *testing.T) {
+	c, err := Compile(types.Rule{Type: types.SourceIP, Action: types.Accept, CIDRs: []string{"10.0.0.0/8", "2001:db8::1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Matchers) != 1 || len(c.Matchers[0].Nets) != 2 {
+		t.Fatalf("got matchers %+v", c.Matchers)
+	}
+	// the regex isn't used when cidr blocks are set
+	if c.Matchers[0].Pattern != nil {
+		t.Fatal("expected no pattern")
+	}
+
+	if _, err := Compile(types.Rule{Type: types.SourceIP, Action: types.Accept, CIDRs: []string{"10.0.0.0/8", "nope"}}); err == nil {
+		t.Fatal("expected an invalid block to fail compilation")
+	}
+}
+
+func TestCompileChecksRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{name: "valid", rule: types.Rule{Type: types.ClusterName, Action: types.Accept, Regex: "^edge-"}},
+		{name: "unknown action", rule: types.Rule{Type: types.All, Action: "Acept"}, wantErr: true},
+		{name: "no action", rule: types.Rule{Type: types.All}, wantErr: true},
+		{name: "unknown type", rule: types.Rule{Type: "Hostname", Action: types.Accept}, wantErr: true},
+		{name: "regex on a secret rule", rule: types.Rule{Type: types.SharedSecret, Action: types.Accept, Regex: "x"}, wantErr: true},
+		{name: "labels without selector", rule: types.Rule{Type: types.Labels, Action: types.Accept}, wantErr: true},
+		{name: "selector on a name rule", rule: types.Rule{Type: types.ClusterName, Action: types.Accept, Selector: "a=b"}, wantErr: true},
+		{name: "secret ids on an ip rule", rule: types.Rule{Type: types.SourceIP, Action: types.Accept, SecretIDs: []string{"s"}}, wantErr: true},
+		{name: "cidrs and regex", rule: types.Rule{Type: types.SourceIP, Action: types.Accept, Regex: "x", CIDRs: []string{"10.0.0.0/8"}}, wantErr: true},
+		{name: "conditions on a simple rule", rule: types.Rule{Type: types.All, Action: types.Accept, Conditions: []types.Condition{{Type: types.All}}}, wantErr: true},
+		{name: "compound without conditions", rule: types.Rule{Type: types.Compound, Action: types.Accept}, wantErr: true},
+		{name: "compound with a regex", rule: types.Rule{Type: types.Compound, Action: types.Accept, Regex: "x", Conditions: []types.Condition{{Type: types.All}}}, wantErr: true},
+		{name: "unknown operator", rule: types.Rule{Type: types.Compound, Action: types.Accept, Operator: "Xor", Conditions: []types.Condition{{Type: types.All}}}, wantErr: true},
+		{name: "nested compound", rule: types.Rule{Type: types.Compound, Action: types.Accept, Conditions: []types.Condition{{Type: types.Compound}}}, wantErr: true},
+		{name: "invalid condition", rule: types.Rule{Type: types.Compound, Action: types.Accept, Conditions: []types.Condition{{Type: types.All}, {Type: types.SharedSecret, Regex: "x"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.rule)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCompiledRulesLogsInvalidRulesOnce(t *testing.T) {
+	var out bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&out)
+
+	backend := storage.NewMemoryBackend()
+	rules := []types.Rule{
+		{ID: "typo", Type: types.All, Action: "Acept"},
+		{ID: "any", Type: types.All, Action: types.Accept},
+	}
+	if err := backend.PutRules(rules); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore(backend, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if c := s.CompiledRules(); c[0].Err == nil {
+			t.Fatal("expected rule typo to fail compilation")
+		}
+		if err := s.RecordMatch("any", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := strings.Count(out.String(), "rule typo"); got != 1 {
+		t.Fatalf("invalid rule logged %d times, want once:\n%s", got, out.String())
+	}
+}
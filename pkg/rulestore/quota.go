@@ -0,0 +1,77 @@
+package rulestore
+
+import (
+	"errors"
+	"github.com/ebauman/moo/pkg/types"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("rule quota exceeded")
+
+// RecentMatches returns how many agents the rule decided within period of now
+func RecentMatches(u types.RuleUsage, period time.Duration, now time.Time) int64 {
+	var n int64
+	for _, t := range u.Recent {
+		if now.Sub(t) < period {
+			n++
+		}
+	}
+
+	return n
+}
+
+func quotaExceeded(r types.Rule, period time.Duration, now time.Time) bool {
+	if r.MaxMatches > 0 && r.Usage.Matches >= r.MaxMatches {
+		return true
+	}
+	if r.RateLimit > 0 && RecentMatches(r.Usage, period, now) >= r.RateLimit {
+		return true
+	}
+
+	return false
+}
+
+// QuotaExceeded reports whether the rule has used up its matches at now
+func (c CompiledRule) QuotaExceeded(now time.Time) bool {
+	return quotaExceeded(c.Rule, c.Period, now)
+}
+
+// RecordMatch counts the rule deciding an agent at now. it fails with
+// ErrQuotaExceeded if the rule has no matches left, which can happen when
+// agents are decided concurrently by the same rule.
+func (s *Store) RecordMatch(id string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := s.backend.ListRules()
+	i := find(rules, id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r := &rules[i]
+
+	// validated when the rule was stored, a bad period only disables the rate limit
+	period, _ := time.ParseDuration(r.RatePeriod)
+	if quotaExceeded(*r, period, now) {
+		return ErrQuotaExceeded
+	}
+
+	r.Usage.Matches++
+
+	// only the matches that count towards the rate limit are kept
+	var recent []time.Time
+	if r.RateLimit > 0 {
+		for _, t := range r.Usage.Recent {
+			if now.Sub(t) < period {
+				recent = append(recent, t)
+			}
+		}
+		recent = append(recent, now)
+		if int64(len(recent)) > r.RateLimit {
+			recent = recent[int64(len(recent))-r.RateLimit:]
+		}
+	}
+	r.Usage.Recent = recent
+
+	return s.backend.PutRules(rules)
+}
@@ -0,0 +1,132 @@
+package rulestore
+
+import (
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
+	"testing"
+	"time"
+)
+
+func testLogger() *log.Logger {
+	logger := log.New()
+	logger.SetLevel(log.PanicLevel)
+
+	return logger
+}
+
+func newTestStore(t *testing.T, r types.Rule) (*Store, types.Rule) {
+	s, err := NewStore(storage.NewMemoryBackend(), testLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = s.AddRule(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, r
+}
+
+func TestRecordMatchMaxMatches(t *testing.T) {
+	s, r := newTestStore(t, types.Rule{Type: types.All, Action: types.Accept, MaxMatches: 2})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if err := s.RecordMatch(r.ID, now); err != nil {
+			t.Fatalf("match %d: %v", i+1, err)
+		}
+	}
+	if err := s.RecordMatch(r.ID, now); err != ErrQuotaExceeded {
+		t.Fatalf("got %v, want %v", err, ErrQuotaExceeded)
+	}
+	if !s.CompiledRules()[0].QuotaExceeded(now) {
+		t.Fatal("expected the quota to be used up")
+	}
+
+	// updating the rule keeps its count, raising the limit lifts the quota
+	r, _ = s.GetRule(r.ID)
+	r.MaxMatches = 3
+	r, err := s.UpdateRule(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Usage.Matches != 2 {
+		t.Fatalf("update reset matches to %d", r.Usage.Matches)
+	}
+	if s.CompiledRules()[0].QuotaExceeded(now) {
+		t.Fatal("expected the raised limit to lift the quota")
+	}
+	if err := s.RecordMatch(r.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordMatch(r.ID, now); err != ErrQuotaExceeded {
+		t.Fatalf("got %v, want %v", err, ErrQuotaExceeded)
+	}
+}
+
+func TestRecordMatchRateLimit(t *testing.T) {
+	s, r := newTestStore(t, types.Rule{Type: types.All, Action: types.Accept, RateLimit: 2, RatePeriod: "1h"})
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		after        time.Duration
+		wantErr      error
+		wantExceeded bool
+	}{
+		{after: 0},
+		{after: time.Minute, wantExceeded: true},
+		{after: 2 * time.Minute, wantErr: ErrQuotaExceeded, wantExceeded: true},
+		// the first match has left the period
+		{after: time.Hour, wantExceeded: true},
+		{after: time.Hour + 30*time.Second, wantErr: ErrQuotaExceeded, wantExceeded: true},
+		// both matches have left the period
+		{after: 3 * time.Hour},
+		{after: 3 * time.Hour, wantExceeded: true},
+		{after: 3 * time.Hour, wantErr: ErrQuotaExceeded, wantExceeded: true},
+	}
+
+	for i, tt := range tests {
+		now := start.Add(tt.after)
+		if err := s.RecordMatch(r.ID, now); err != tt.wantErr {
+			t.Fatalf("match %d at +%s: got %v, want %v", i+1, tt.after, err, tt.wantErr)
+		}
+		if got := s.CompiledRules()[0].QuotaExceeded(now); got != tt.wantExceeded {
+			t.Fatalf("match %d at +%s: got quota exceeded %v, want %v", i+1, tt.after, got, tt.wantExceeded)
+		}
+	}
+
+	r, _ = s.GetRule(r.ID)
+	if r.Usage.Matches != 5 {
+		t.Fatalf("got %d matches, want 5", r.Usage.Matches)
+	}
+	if len(r.Usage.Recent) != 2 {
+		t.Fatalf("kept %d recent matches, want at most the rate limit", len(r.Usage.Recent))
+	}
+}
+
+func TestCompiledRulesNotRecompiledOnMatch(t *testing.T) {
+	s, r := newTestStore(t, types.Rule{Type: types.ClusterName, Action: types.Accept, Regex: "^edge-", MaxMatches: 5})
+
+	before := s.CompiledRules()[0]
+	if err := s.RecordMatch(r.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	after := s.CompiledRules()[0]
+
+	if after.Matchers[0].Pattern != before.Matchers[0].Pattern {
+		t.Fatal("rule was recompiled after a match")
+	}
+	if after.Usage.Matches != 1 {
+		t.Fatalf("compiled rule has %d matches, want 1", after.Usage.Matches)
+	}
+
+	r, _ = s.GetRule(r.ID)
+	r.Regex = "^core-"
+	if _, err := s.UpdateRule(r); err != nil {
+		t.Fatal(err)
+	}
+	if s.CompiledRules()[0].Matchers[0].Pattern.String() != "^core-" {
+		t.Fatal("rule was not recompiled after an update")
+	}
+}
@@ -1,44 +1,228 @@
 package rulestore
 
 import (
+	"errors"
+	"github.com/ebauman/moo/pkg/keys"
+	"github.com/ebauman/moo/pkg/storage"
 	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
 	"sort"
+	"sync"
+	"time"
 )
 
+const (
+	idPrefix = "rule-"
+	idLength = 6
+)
+
+var (
+	ErrNotFound = errors.New("rule not found")
+	ErrConflict = errors.New("rule has been modified")
+)
+
+// Store is safe for concurrent use. modifications are read-modify-write
+// against the backend and are serialized. every rule has a stable id, and a
+// resource version that is bumped on each update so concurrent writers can't
+// clobber each other.
 type Store struct {
-	rules []types.Rule
+	mu      sync.RWMutex
+	backend storage.Backend
+	log     *log.Logger
+
+	compiledMu   sync.Mutex
+	compiled     []CompiledRule
+	compiledFrom []types.Rule
 }
 
-func NewStore() *Store {
-	return &Store{
-		rules: make([]types.Rule, 0),
+func NewStore(backend storage.Backend, log *log.Logger) (*Store, error) {
+	s := &Store{
+		backend: backend,
+		log:     log,
+	}
+
+	if err := s.assignIDs(); err != nil {
+		return nil, err
 	}
+
+	return s, nil
 }
 
-func (s *Store) AddRule(r types.Rule) bool {
-	s.rules = append(s.rules, types.Rule{})
-	if len(s.rules) == 0 {
-		s.rules = append(s.rules, r)
-		return true
+// assignIDs gives rules stored before rules had ids (or added straight to the
+// backend, e.g. with kubectl) an id and a resource version. rules stored
+// before they had a creation time get one that keeps their current order.
+func (s *Store) assignIDs() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := s.backend.ListRules()
+	changed := false
+	now := time.Now()
+	for i := range rules {
+		if rules[i].Created.IsZero() {
+			rules[i].Created = now.Add(time.Duration(i))
+			changed = true
+		}
+		if rules[i].ID == "" {
+			id, err := newID()
+			if err != nil {
+				return err
+			}
+			rules[i].ID = id
+			changed = true
+		}
+		if rules[i].ResourceVersion == 0 {
+			rules[i].ResourceVersion = 1
+			changed = true
+		}
 	}
 
-	i := sort.Search(len(s.rules), func(i int) bool {
-		return s.rules[i].Priority < r.Priority
-	})
+	if !changed {
+		return nil
+	}
+
+	return s.backend.PutRules(rules)
+}
+
+// AddRule inserts a rule after any rules of equal or higher priority and
+// returns it as stored, with its id and resource version set
+func (s *Store) AddRule(r types.Rule) (types.Rule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := newID()
+	if err != nil {
+		return types.Rule{}, err
+	}
+	r.ID = id
+	r.ResourceVersion = 1
+	r.Created = time.Now()
+	r.Usage = types.RuleUsage{}
+
+	rules := append(s.backend.ListRules(), r)
+	sortRules(rules)
+
+	if err := s.backend.PutRules(rules); err != nil {
+		return types.Rule{}, err
+	}
+
+	return s.stored(r), nil
+}
+
+func (s *Store) GetRule(id string) (types.Rule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := s.backend.ListRules()
+	if i := find(rules, id); i >= 0 {
+		return rules[i], true
+	}
+
+	return types.Rule{}, false
+}
+
+// UpdateRule replaces the rule with the same id, keeping its usage and
+// creation time. the resource version must match the stored rule, otherwise
+// ErrConflict is returned. a rule whose priority changes keeps its place by
+// age among the rules of its new priority.
+func (s *Store) UpdateRule(r types.Rule) (types.Rule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	copy(s.rules[i+1:], s.rules[i:])
+	rules := s.backend.ListRules()
+	i := find(rules, r.ID)
+	if i < 0 {
+		return types.Rule{}, ErrNotFound
+	}
+	if rules[i].ResourceVersion != r.ResourceVersion {
+		return types.Rule{}, ErrConflict
+	}
+
+	r.ResourceVersion++
+	r.Created = rules[i].Created
+	r.Usage = rules[i].Usage
+	rules[i] = r
+	sortRules(rules)
+
+	if err := s.backend.PutRules(rules); err != nil {
+		return types.Rule{}, err
+	}
+
+	return s.stored(r), nil
+}
 
-	s.rules[i] = r
+// stored returns a rule as the backend stored it, which may have set its own
+// resource version and creation time. callers must hold the lock.
+func (s *Store) stored(r types.Rule) types.Rule {
+	rules := s.backend.ListRules()
+	if i := find(rules, r.ID); i >= 0 {
+		return rules[i]
+	}
 
-	return true
+	return r
 }
 
+// ListRules returns a copy of the rules in evaluation order
 func (s *Store) ListRules() []types.Rule {
-	return s.rules
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.backend.ListRules()
 }
 
-func (s *Store) DeleteRule(index int) bool {
-	s.rules = append(s.rules[:index], s.rules[index+1:]...)
+// DeleteRule removes the rule with the given id. if resourceVersion is not
+// zero it must match the stored rule, otherwise ErrConflict is returned.
+func (s *Store) DeleteRule(id string, resourceVersion int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return true
-}
\ No newline at end of file
+	rules := s.backend.ListRules()
+
+	i := find(rules, id)
+	if i < 0 {
+		return false, nil
+	}
+	if resourceVersion != 0 && rules[i].ResourceVersion != resourceVersion {
+		return false, ErrConflict
+	}
+
+	if err := s.backend.DeleteRule(id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sortRules puts rules in evaluation order: highest priority first, then
+// oldest first, then by id. the kubernetes backend derives the same order
+// from its objects.
+func sortRules(rules []types.Rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		if !rules[i].Created.Equal(rules[j].Created) {
+			return rules[i].Created.Before(rules[j].Created)
+		}
+		return rules[i].ID < rules[j].ID
+	})
+}
+
+func find(rules []types.Rule, id string) int {
+	for i, r := range rules {
+		if r.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func newID() (string, error) {
+	id, err := keys.Random(idLength)
+	if err != nil {
+		return "", err
+	}
+
+	return idPrefix + id, nil
+}
@@ -0,0 +1,250 @@
+package rulestore
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxWindowDuration bounds how long a window may stay open. finding out if a
+// window is open means looking back over its duration a day at a time.
+const MaxWindowDuration = 7 * 24 * time.Hour
+
+// Schedule is a parsed cron expression. each field is a bitset of the values
+// it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// day of month and day of week match if either does, unless one is *
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseSchedule parses a five field cron expression. fields may be *, values,
+// ranges (1-5), steps (*/15, 0-30/10) and lists of those (1,15). sunday is
+// day 0 or 7.
+func ParseSchedule(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return Schedule{}, fmt.Errorf("schedule %q must have %d fields, has %d", expr, len(cronFields), len(fields))
+	}
+
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("schedule %q: %v", expr, err)
+		}
+		bits[i] = b
+	}
+
+	// sunday may be written as 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return Schedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, part[i+1:])
+			}
+			rng, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid %s %q", f.name, rng)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid %s %q", f.name, rng)
+				}
+			} else if step > 1 {
+				// 5/15 means from 5 to the end, every 15
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s %q out of range %d-%d", f.name, rng, f.min, f.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// Matches reports whether the minute t falls in matches the schedule
+func (s Schedule) Matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 {
+		return false
+	}
+
+	return s.matchesDay(t)
+}
+
+// matchesDay reports whether the schedule matches some minute of the day t
+// falls on
+func (s Schedule) matchesDay(t time.Time) bool {
+	if s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Window is a parsed window of a rule
+type Window struct {
+	types.Window
+	Schedule Schedule
+	Length   time.Duration
+}
+
+// ParseWindow parses the schedule and duration of a window
+func ParseWindow(w types.Window) (Window, error) {
+	schedule, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return Window{}, err
+	}
+
+	length, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid duration %q", w.Duration)
+	}
+	if length < time.Minute || length > MaxWindowDuration {
+		return Window{}, fmt.Errorf("duration %s must be between 1m and %s", length, MaxWindowDuration)
+	}
+
+	return Window{Window: w, Schedule: schedule, Length: length}, nil
+}
+
+// Open reports whether the window is open at t, that is the schedule matched
+// a minute that started less than the window's length before t
+func (w Window) Open(t time.Time, loc *time.Location) bool {
+	start, ok := w.Schedule.last(t.In(loc), w.Length)
+	return ok && t.Sub(start) < w.Length
+}
+
+// last finds the latest minute at or before t the schedule matches, looking
+// back no further than the days within limit of t. it goes back a day at a
+// time and picks the hour and minute straight from the bitsets.
+func (s Schedule) last(t time.Time, limit time.Duration) (time.Time, bool) {
+	loc := t.Location()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+	for today := true; t.Sub(day.AddDate(0, 0, 1)) < limit; day, today = day.AddDate(0, 0, -1), false {
+		if !s.matchesDay(day) {
+			continue
+		}
+
+		maxHour := 23
+		if today {
+			maxHour = t.Hour()
+		}
+		for h := latest(s.hour, maxHour); h >= 0; h = latest(s.hour, h-1) {
+			maxMinute := 59
+			if today && h == t.Hour() {
+				maxMinute = t.Minute()
+			}
+			m := latest(s.minute, maxMinute)
+			if m < 0 {
+				continue
+			}
+
+			// skips times that don't exist because of daylight saving
+			start := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+			if start.After(t) {
+				continue
+			}
+
+			return start, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// latest returns the highest value up to max set in a field's bitset, -1 if
+// there is none
+func latest(set uint64, max int) int {
+	if max < 0 {
+		return -1
+	}
+
+	return bits.Len64(set&(1<<uint(max+1)-1)) - 1
+}
+
+// Active reports whether a compiled rule applies at t: it is within the
+// rule's NotBefore and NotAfter, and one of its windows is open if it has any
+func (c CompiledRule) Active(t time.Time) bool {
+	if c.NotBefore != nil && t.Before(*c.NotBefore) {
+		return false
+	}
+	if c.NotAfter != nil && !t.Before(*c.NotAfter) {
+		return false
+	}
+	if len(c.Windows) == 0 {
+		return true
+	}
+
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, w := range c.ParsedWindows {
+		if w.Open(t, loc) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,158 @@
+package rulestore
+
+import (
+	"github.com/ebauman/moo/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		expr    string
+		match   []string
+		noMatch []string
+		wantErr bool
+	}{
+		{
+			expr:  "* * * * *",
+			match: []string{"2021-03-01T00:00:00Z", "2021-12-31T23:59:00Z"},
+		},
+		{
+			expr:    "*/15 9-17 * * 1-5",
+			match:   []string{"2021-03-01T09:00:00Z", "2021-03-05T17:45:00Z"},
+			noMatch: []string{"2021-03-01T09:05:00Z", "2021-03-01T18:00:00Z", "2021-03-06T09:00:00Z"},
+		},
+		{
+			expr:    "0 0 * * 7",
+			match:   []string{"2021-03-07T00:00:00Z"},
+			noMatch: []string{"2021-03-06T00:00:00Z"},
+		},
+		{
+			// day of month or day of week when neither is *
+			expr:    "30 6 1 * 1",
+			match:   []string{"2021-03-01T06:30:00Z", "2021-03-08T06:30:00Z", "2021-04-01T06:30:00Z"},
+			noMatch: []string{"2021-03-02T06:30:00Z"},
+		},
+		{
+			expr:    "5/20 0 1,15 2 *",
+			match:   []string{"2021-02-01T00:05:00Z", "2021-02-15T00:45:00Z"},
+			noMatch: []string{"2021-02-01T00:00:00Z", "2021-03-01T00:05:00Z", "2021-02-02T00:05:00Z"},
+		},
+		{expr: "* * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* 5-1 * * *", wantErr: true},
+		{expr: "* * 0 * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "a * * * *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseSchedule(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, m := range tt.match {
+				if !s.Matches(parseTime(t, m)) {
+					t.Errorf("expected a match at %s", m)
+				}
+			}
+			for _, m := range tt.noMatch {
+				if s.Matches(parseTime(t, m)) {
+					t.Errorf("unexpected match at %s", m)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		duration string
+		at       string
+		want     bool
+	}{
+		{"at the start", "0 9 * * *", "1h", "2021-03-01T09:00:00Z", true},
+		{"before the start", "0 9 * * *", "1h", "2021-03-01T08:59:59Z", false},
+		{"just before the end", "0 9 * * *", "1h", "2021-03-01T09:59:59Z", true},
+		{"at the end", "0 9 * * *", "1h", "2021-03-01T10:00:00Z", false},
+		{"across midnight", "30 23 * * *", "2h", "2021-03-02T01:00:00Z", true},
+		{"across a month", "0 22 31 * *", "4h", "2021-04-01T01:00:00Z", true},
+		{"weekly, days later", "0 18 * * 5", "72h", "2021-03-08T17:00:00Z", true},
+		{"weekly, too late", "0 18 * * 5", "72h", "2021-03-08T18:00:00Z", false},
+		{"a week long", "0 0 * * 1", "168h", "2021-03-07T23:59:00Z", true},
+		{"latest start counts", "0,50 9 * * *", "15m", "2021-03-01T09:55:00Z", true},
+		{"between starts", "0,50 9 * * *", "15m", "2021-03-01T09:20:00Z", false},
+		{"never that month", "0 0 * 2 *", "1h", "2021-03-01T00:30:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWindow(types.Window{Schedule: tt.schedule, Duration: tt.duration})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			at := parseTime(t, tt.at)
+			if got := w.Open(at, time.UTC); got != tt.want {
+				t.Fatalf("open at %s: got %t, want %t", tt.at, got, tt.want)
+			}
+			if got := openByMinute(w, at, time.UTC); got != tt.want {
+				t.Fatalf("looking back a minute at a time disagrees at %s", tt.at)
+			}
+		})
+	}
+}
+
+func TestWindowOpenInZone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	w, err := ParseWindow(types.Window{Schedule: "*/20 1-3 * * *", Duration: "45m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// around the switches to and from daylight saving time
+	for _, start := range []time.Time{time.Date(2021, 3, 13, 0, 0, 0, 0, loc), time.Date(2021, 11, 6, 0, 0, 0, 0, loc)} {
+		for at := start; at.Before(start.Add(48 * time.Hour)); at = at.Add(7 * time.Minute) {
+			if got, want := w.Open(at, loc), openByMinute(w, at, loc); got != want {
+				t.Fatalf("open at %s: got %t, want %t", at, got, want)
+			}
+		}
+	}
+}
+
+// openByMinute checks every minute in the window's length before t
+func openByMinute(w Window, t time.Time, loc *time.Location) bool {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+
+	for m := start; t.Sub(m) < w.Length; m = m.Add(-time.Minute) {
+		if w.Schedule.Matches(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseTime(t *testing.T, s string) time.Time {
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return at
+}
@@ -0,0 +1,100 @@
+package secretstore
+
+import (
+	"crypto/subtle"
+	"github.com/ebauman/moo/pkg/keys"
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/ebauman/moo/pkg/types"
+	"sync"
+	"time"
+)
+
+const (
+	idLength     = 6
+	secretLength = 32
+)
+
+// Store keeps the set of shared secrets that SharedSecret rules match against.
+// secrets are only held as sha256 hashes.
+type Store struct {
+	mu      sync.RWMutex
+	backend storage.Backend
+}
+
+func NewStore(backend storage.Backend) *Store {
+	return &Store{
+		backend: backend,
+	}
+}
+
+// CreateSecret mints a new secret and returns it along with the secret value,
+// which cannot be recovered later
+func (s *Store) CreateSecret(description string) (types.Secret, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := keys.Random(idLength)
+	if err != nil {
+		return types.Secret{}, "", err
+	}
+	value, err := keys.Random(secretLength)
+	if err != nil {
+		return types.Secret{}, "", err
+	}
+
+	secret := types.Secret{
+		ID:          id,
+		Hash:        keys.Hash(value),
+		Description: description,
+		Created:     time.Now(),
+	}
+
+	if err := s.backend.PutSecret(secret); err != nil {
+		return types.Secret{}, "", err
+	}
+
+	return secret, value, nil
+}
+
+// Match returns the stored secret with the given hash, or nil. every stored
+// secret is compared in constant time so timing doesn't reveal which matched.
+func (s *Store) Match(hash string) *types.Secret {
+	if hash == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched *types.Secret
+	for _, v := range s.backend.ListSecrets() {
+		v := v
+		if subtle.ConstantTimeCompare([]byte(v.Hash), []byte(hash)) == 1 {
+			matched = &v
+		}
+	}
+
+	return matched
+}
+
+func (s *Store) ListSecrets() []types.Secret {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.backend.ListSecrets()
+}
+
+func (s *Store) DeleteSecret(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.backend.GetSecret(id); !ok {
+		return false, nil
+	}
+
+	if err := s.backend.DeleteSecret(id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
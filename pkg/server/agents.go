@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/ebauman/moo/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// RequeueAgent sends a denied, held or errored agent back to pending, so the
+// rules are evaluated against it again
+func (s *Server) RequeueAgent(ctx context.Context, req *rpc.AgentAction) (*rpc.Agent, error) {
+	actor := callerFromContext(ctx).Name
+
+	agent, err := s.adminTransition(req, actor, "requeued", types.StatusPending, types.StatusDenied, types.StatusHeld, types.StatusError)
+	if err != nil {
+		return nil, err
+	}
+
+	s.enqueueAgent(agent.ID)
+
+	return agentToRPC(*agent), nil
+}
+
+// ApproveAgent accepts an agent regardless of the rules. the agent is
+// registered with rancher by the controller. with AllowAdoption the agent may
+// also adopt an existing cluster that isn't bound to it.
+func (s *Server) ApproveAgent(ctx context.Context, req *rpc.AgentAction) (*rpc.Agent, error) {
+	actor := callerFromContext(ctx).Name
+
+	verb := "approved"
+	if req.GetAllowAdoption() {
+		verb = "approved with adoption"
+	}
+
+	agent, err := s.adminTransition(req, actor, verb, types.StatusAccepted, types.StatusPending, types.StatusHeld, types.StatusDenied, types.StatusError)
+	if err != nil {
+		return nil, err
+	}
+
+	s.enqueueAgent(agent.ID)
+
+	return agentToRPC(*agent), nil
+}
+
+// DenyAgent denies an agent regardless of the rules
+func (s *Server) DenyAgent(ctx context.Context, req *rpc.AgentAction) (*rpc.Agent, error) {
+	actor := callerFromContext(ctx).Name
+
+	agent, err := s.adminTransition(req, actor, "denied", types.StatusDenied, types.StatusPending, types.StatusHeld, types.StatusAccepted, types.StatusRegistered, types.StatusError)
+	if err != nil {
+		return nil, err
+	}
+
+	return agentToRPC(*agent), nil
+}
+
+// HoldAgent holds an agent. unlike a hold from a rule, the rules are not
+// evaluated against it again until it is requeued.
+func (s *Server) HoldAgent(ctx context.Context, req *rpc.AgentAction) (*rpc.Agent, error) {
+	actor := callerFromContext(ctx).Name
+
+	agent, err := s.adminTransition(req, actor, "held", types.StatusHeld, types.StatusPending, types.StatusDenied, types.StatusAccepted, types.StatusRegistered, types.StatusError)
+	if err != nil {
+		return nil, err
+	}
+
+	return agentToRPC(*agent), nil
+}
+
+// DeleteAgent removes an agent from the store. an agent that registers again
+// afterwards starts over as pending.
+func (s *Server) DeleteAgent(ctx context.Context, req *rpc.AgentAction) (*rpc.DeleteResponse, error) {
+	if s.agentStore.GetAgent(req.GetID()) == nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.GetID())
+	}
+
+	if err := s.agentStore.RemoveAgent(req.GetID()); err != nil {
+		return nil, status.Errorf(codes.Internal, "error deleting agent: %v", err)
+	}
+
+	message := fmt.Sprintf("deleted by %s", callerFromContext(ctx).Name)
+	if req.GetReason() != "" {
+		message = fmt.Sprintf("%s: %s", message, req.GetReason())
+	}
+	s.log.Infof("agent %s %s", req.GetID(), message)
+
+	return &rpc.DeleteResponse{Success: true}, nil
+}
+
+// adminTransition moves an agent to a new status on behalf of an admin, if it
+// is currently in one of the given statuses. the actor and reason are
+// recorded in the status message. agents sent back to pending are left to
+// the rules, any other status is kept until an admin changes it.
+func (s *Server) adminTransition(req *rpc.AgentAction, actor string, verb string, to types.Status, from ...types.Status) (*types.Agent, error) {
+	message := fmt.Sprintf("%s by %s", verb, actor)
+	if req.GetReason() != "" {
+		message = fmt.Sprintf("%s: %s", message, req.GetReason())
+	}
+
+	changed := false
+	agent, err := s.agentStore.UpdateAgentFunc(req.GetID(), func(a *types.Agent) bool {
+		for _, f := range from {
+			if a.Status == f {
+				a.Status = to
+				a.StatusMessage = message
+				a.DecidedBy = actor
+				a.AdoptionAllowedBy = ""
+				if to == types.StatusAccepted && req.GetAllowAdoption() {
+					a.AdoptionAllowedBy = actor
+				}
+				if to == types.StatusPending {
+					a.PendingSince = time.Now()
+					a.DecidedBy = ""
+				}
+				changed = true
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error updating agent: %v", err)
+	}
+	if agent == nil {
+		return nil, status.Errorf(codes.NotFound, "agent %s not found", req.GetID())
+	}
+	if !changed {
+		return nil, status.Errorf(codes.FailedPrecondition, "agent %s is %s", req.GetID(), agent.Status)
+	}
+
+	s.log.Infof("agent %s %s", req.GetID(), message)
+
+	return agent, nil
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// peerIdentity returns the identity of a caller that presented a client
+// certificate the server verified: the subject common name, or the first
+// dns or uri san when there is no common name. unverified callers have no identity.
+func peerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return ""
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	switch {
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	}
+
+	return ""
+}
+
+type callerKey struct{}
+
+// Caller is whoever made an rpc, as established by authenticate
+type Caller struct {
+	Role types.Role
+	Name string
+}
+
+// agentMethods may be called by agents. everything else, including ListAgents
+// and every method on the Rules and Tokens services, is admin only, as is
+// any method we don't know about.
+var agentMethods = map[string]bool{
+	"/Moo/GetAgentStatus":   true,
+	"/Moo/RegisterAgent":    true,
+	"/Moo/GetManifestURL":   true,
+	"/Moo/WatchAgentStatus": true,
+}
+
+func requiredRole(method string) types.Role {
+	if agentMethods[method] {
+		return types.RoleAgent
+	}
+
+	return types.RoleAdmin
+}
+
+// authenticate works out who is calling, from a bearer token if one is sent
+// and otherwise from a verified client certificate
+func (s *Server) authenticate(ctx context.Context) (Caller, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, v := range md.Get("authorization") {
+			if !strings.HasPrefix(v, "Bearer ") {
+				continue
+			}
+			bearer := strings.TrimPrefix(v, "Bearer ")
+
+			if s.config.AdminToken != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(s.config.AdminToken)) == 1 {
+				return Caller{Role: types.RoleAdmin, Name: "admin-token"}, nil
+			}
+
+			t := s.tokenStore.Authenticate(bearer)
+			if t == nil {
+				// a bad token is an error rather than a fallback to anonymous
+				return Caller{}, status.Error(codes.Unauthenticated, "invalid bearer token")
+			}
+
+			return Caller{Role: t.Role, Name: fmt.Sprintf("token:%s", t.ID)}, nil
+		}
+	}
+
+	if identity := peerIdentity(ctx); identity != "" {
+		for _, admin := range s.config.AdminIdentities {
+			if admin == identity {
+				return Caller{Role: types.RoleAdmin, Name: identity}, nil
+			}
+		}
+
+		return Caller{Role: types.RoleAgent, Name: identity}, nil
+	}
+
+	return Caller{Role: types.RoleNone}, nil
+}
+
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	caller, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch requiredRole(method) {
+	case types.RoleAgent:
+		if caller.Role == types.RoleNone && s.config.RequireAgentAuth {
+			return nil, status.Errorf(codes.Unauthenticated, "%s requires an agent token or client certificate", method)
+		}
+	case types.RoleAdmin:
+		if caller.Role == types.RoleNone {
+			return nil, status.Errorf(codes.Unauthenticated, "%s requires an admin token or client certificate", method)
+		}
+		if caller.Role != types.RoleAdmin {
+			return nil, status.Errorf(codes.PermissionDenied, "%s requires the %s role", method, types.RoleAdmin)
+		}
+	}
+
+	s.log.Tracef("authorized %s (role: %s) for %s", caller.Name, caller.Role, method)
+
+	return context.WithValue(ctx, callerKey{}, caller), nil
+}
+
+func callerFromContext(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
+
+func (s *Server) AuthorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (s *Server) AuthorizeStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _, err := s.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	"sync"
+	"time"
+)
+
+// agents are reconciled one at a time off a rate limited work queue. rpc
+// handlers enqueue the agents they affect as soon as something changes, and
+// every agent is enqueued again on each resync in case anything was missed.
+// an agent whose reconcile fails is retried with a per-agent exponential backoff.
+
+func (s *Server) Run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer s.queue.ShutDown()
+
+	workers := s.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go s.runWorker()
+	}
+
+	resync := time.Duration(s.config.ResyncTime) * time.Second
+	if resync <= 0 {
+		resync = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(resync)
+	defer ticker.Stop()
+
+	for {
+		s.enqueueAll()
+		<-ticker.C
+	}
+}
+
+func (s *Server) runWorker() {
+	for s.processNextItem() {
+	}
+}
+
+func (s *Server) processNextItem() bool {
+	key, quit := s.queue.Get()
+	if quit {
+		return false
+	}
+	defer s.queue.Done(key)
+
+	id := key.(string)
+	if err := s.reconcileAgent(id); err != nil {
+		s.log.Errorf("error reconciling agent %s, retrying: %v", id, err)
+		s.queue.AddRateLimited(key)
+		return true
+	}
+
+	s.queue.Forget(key)
+	return true
+}
+
+func (s *Server) enqueueAgent(id string) {
+	s.queue.Add(id)
+}
+
+func (s *Server) enqueueAgentsByStatus(statuses ...types.Status) {
+	for _, status := range statuses {
+		for _, a := range s.agentStore.ListAgentsByStatus(status) {
+			s.queue.Add(a.ID)
+		}
+	}
+}
+
+// enqueueUndecided enqueues the agents a change to the rules may decide on
+func (s *Server) enqueueUndecided() {
+	s.enqueueAgentsByStatus(types.StatusPending, types.StatusHeld)
+}
+
+func (s *Server) enqueueAll() {
+	s.log.Tracef("resyncing all agents")
+	for _, a := range s.agentStore.ListAgents() {
+		s.queue.Add(a.ID)
+	}
+}
+
+// reconcileAgent applies rules to pending and held agents, registers accepted
+// agents with rancher and follows their clusters there until they are active.
+// returned errors cause the agent to be retried.
+func (s *Server) reconcileAgent(id string) error {
+	a := s.agentStore.GetAgent(id)
+	if a == nil {
+		return nil
+	}
+
+	if a.Status == types.StatusPending || a.Status == types.StatusHeld {
+		var err error
+		if a.Status == types.StatusPending {
+			err = s.applyRules(a, s.ruleStore.CompiledRules())
+		} else {
+			err = s.reapplyRules(a, s.ruleStore.CompiledRules())
+		}
+		if err != nil {
+			return err
+		}
+
+		// pick up the outcome of the rules
+		if a = s.agentStore.GetAgent(id); a == nil {
+			return nil
+		}
+	}
+
+	// accepted clusters shall be registered
+	if a.Status == types.StatusAccepted && a.ManifestUrl == "" {
+		if err := s.registerAgent(a); err != nil {
+			return s.transitionAgent(a.ID, types.StatusAccepted, types.StatusError, fmt.Sprintf("error registering agent: %v", err))
+		}
+		return nil
+	}
+
+	// agents accepted before clusters were recorded on them can't be followed
+	if a.Status == types.StatusAccepted && a.RancherClusterID != "" {
+		return s.trackCluster(a)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/ebauman/moo/pkg/keys"
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/ebauman/moo/pkg/rulestore"
+	"github.com/ebauman/moo/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// ruleResult is the outcome of evaluating one rule against an agent
+type ruleResult struct {
+	rule       rulestore.CompiledRule
+	active     bool
+	matched    bool
+	overQuota  bool
+	conditions []bool
+}
+
+// decision is the outcome of evaluating the rules against an agent. rule is
+// nil when no rule matched, and the default action applies. overQuota is set
+// when the rule matched but has used up its matches, and holds the agent.
+type decision struct {
+	results   []ruleResult
+	rule      *rulestore.CompiledRule
+	overQuota bool
+	status    types.Status
+	message   string
+}
+
+// evaluateRules runs the rules active at now in order against an agent without
+// changing anything. the first matching rule decides. unless all is set
+// evaluation stops there, otherwise every rule is evaluated and reported.
+func (s *Server) evaluateRules(a *types.Agent, rules []rulestore.CompiledRule, now time.Time, all bool) decision {
+	d := decision{
+		status: types.StatusPending,
+	}
+
+	for i := range rules {
+		r := rules[i]
+		// invalid rules were logged when they were compiled
+		if r.Err != nil {
+			d.results = append(d.results, ruleResult{rule: r})
+			continue
+		}
+		if !r.Active(now) {
+			d.results = append(d.results, ruleResult{rule: r})
+			continue
+		}
+
+		matched, conditions := s.evalRule(a, r)
+		overQuota := matched && r.QuotaExceeded(now)
+		d.results = append(d.results, ruleResult{rule: r, active: true, matched: matched, overQuota: overQuota, conditions: conditions})
+
+		// a rule over its quota doesn't match, unless it holds what it matches
+		if overQuota && !r.HoldOverQuota {
+			continue
+		}
+
+		if matched && d.rule == nil {
+			d.rule = &rules[i]
+			d.status = actionStatus(r.Action)
+			d.message = fmt.Sprintf("%s per rule %s (type: %s)", d.status, r.ID, r.Type)
+			if overQuota {
+				d.overQuota = true
+				d.status = types.StatusHeld
+				d.message = fmt.Sprintf("%s per rule %s (type: %s), quota exceeded", d.status, r.ID, r.Type)
+			}
+			if !all {
+				break
+			}
+		}
+	}
+
+	if d.rule == nil {
+		d.status = s.defaultStatus
+		if len(rules) == 0 {
+			d.message = fmt.Sprintf("%s, no rules to evaluate", d.status)
+		} else {
+			d.message = fmt.Sprintf("%s, no rule matched", d.status)
+		}
+	}
+
+	return d
+}
+
+// recordMatch counts the agent the rule of a decision is about to decide
+// against the rule's quota. an agent held because the quota was used up isn't
+// counted. the error is returned when the quota ran out in the meantime, so
+// the agent is retried and evaluated again.
+func (s *Server) recordMatch(d decision, now time.Time) error {
+	if d.rule == nil || d.overQuota {
+		return nil
+	}
+
+	if err := s.ruleStore.RecordMatch(d.rule.ID, now); err != nil {
+		return fmt.Errorf("rule %s: %v", d.rule.ID, err)
+	}
+
+	return nil
+}
+
+func actionStatus(action types.RuleAction) types.Status {
+	switch action {
+	case types.Accept:
+		return types.StatusAccepted
+	case types.Hold:
+		return types.StatusHeld
+	case types.Deny:
+		return types.StatusDenied
+	}
+
+	return types.StatusUnknown
+}
+
+// EvaluateRules explains what the rules would decide for an agent. the agent
+// is either an existing one, a hypothetical one, or an existing one with some
+// of its fields overridden. nothing is changed.
+func (s *Server) EvaluateRules(ctx context.Context, req *rpc.EvaluateRequest) (*rpc.EvaluateResponse, error) {
+	agent := &types.Agent{}
+	if req.GetAgentID() != "" {
+		agent = s.agentStore.GetAgent(req.GetAgentID())
+		if agent == nil {
+			return nil, status.Errorf(codes.NotFound, "agent %s not found", req.GetAgentID())
+		}
+	}
+
+	if req.GetClusterName() != "" {
+		agent.ClusterName = req.GetClusterName()
+	}
+	if req.GetIP() != "" {
+		agent.IP = req.GetIP()
+	}
+	if req.GetSecret() != "" {
+		agent.SecretHash = keys.Hash(req.GetSecret())
+	}
+	if len(req.GetLabels()) > 0 {
+		if err := validateLabels(req.GetLabels()); err != nil {
+			return nil, err
+		}
+		agent.Labels = req.GetLabels()
+	}
+
+	now := time.Now()
+	if req.GetAt() != "" {
+		at, err := time.Parse(time.RFC3339, req.GetAt())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid time %q, expected RFC 3339", req.GetAt())
+		}
+		now = at
+	}
+
+	d := s.evaluateRules(agent, s.ruleStore.CompiledRules(), now, true)
+
+	resp := &rpc.EvaluateResponse{
+		Status:  statusToRPC(d.status),
+		Message: d.message,
+	}
+	if d.rule != nil {
+		resp.RuleID = d.rule.ID
+	}
+
+	for _, r := range d.results {
+		result := &rpc.RuleResult{
+			Rule:       compiledRuleToRpc(r.rule, now),
+			Active:     r.active,
+			Matched:    r.matched,
+			OverQuota:  r.overQuota,
+			Conditions: r.conditions,
+		}
+		if r.rule.Err != nil {
+			result.Error = r.rule.Err.Error()
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/rancher"
+	"github.com/ebauman/moo/pkg/types"
+	"strings"
+	"text/template"
+)
+
+// what happens when the name of a new rancher cluster is already taken
+const (
+	collisionFail   = "fail"   // the agent errors, unless it asked to use the existing cluster
+	collisionSuffix = "suffix" // the first free name of name-2, name-3, ... is used
+	collisionAdopt  = "adopt"  // the existing cluster is used, if the agent may adopt it
+)
+
+const defaultNameTemplate = "{{.ClusterName}}"
+
+// how many suffixed names are tried before giving up
+const maxNameSuffix = 100
+
+// nameData is what the cluster name template is executed with
+type nameData struct {
+	ClusterName string
+	ID          string
+	ShortID     string
+	IP          string
+	Labels      map[string]string
+}
+
+func parseNameTemplate(s string) (*template.Template, error) {
+	if s == "" {
+		s = defaultNameTemplate
+	}
+
+	// labels an agent doesn't report are empty rather than "<no value>"
+	return template.New("cluster-name").Option("missingkey=zero").Parse(s)
+}
+
+func parseCollision(s string) (string, error) {
+	switch s {
+	case "":
+		return collisionFail, nil
+	case collisionFail, collisionSuffix, collisionAdopt:
+		return s, nil
+	}
+
+	return "", fmt.Errorf("invalid strategy %s", s)
+}
+
+// shortID is the first 8 letters and digits of the agent id, lowercased
+func shortID(id string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(id) {
+		if b.Len() == 8 {
+			break
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// clusterName executes the cluster name template for an agent
+func (s *Server) clusterName(a *types.Agent) (string, error) {
+	labels := a.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	var b strings.Builder
+	err := s.nameTemplate.Execute(&b, nameData{
+		ClusterName: a.ClusterName,
+		ID:          a.ID,
+		ShortID:     shortID(a.ID),
+		IP:          a.IP,
+		Labels:      labels,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cluster name template: %v", err)
+	}
+
+	name := strings.TrimSpace(b.String())
+	if name == "" {
+		return "", fmt.Errorf("cluster name template gave an empty name")
+	}
+
+	return name, nil
+}
+
+// reconcileCluster finds or creates the rancher cluster for an agent, naming
+// it from the template and resolving collisions with the configured strategy.
+// an agent asking to use an existing cluster gets it if it proves it is bound
+// to the cluster, or an admin allowed the adoption.
+func (s *Server) reconcileCluster(a *types.Agent) (id string, name string, created bool, err error) {
+	if name, err = s.clusterName(a); err != nil {
+		return "", "", false, err
+	}
+
+	// an admin allowing adoption means the agent is meant to have the existing cluster
+	useExisting := a.UseExisting || a.AdoptionAllowedBy != ""
+	switch {
+	case s.collision == collisionAdopt:
+		useExisting = true
+	case s.collision == collisionSuffix && !useExisting:
+		if name, err = s.rancher.FreeClusterName(name, maxNameSuffix); err != nil {
+			return "", "", false, err
+		}
+	}
+
+	claim := &rancher.Claim{
+		AgentID:    a.ID,
+		SecretHash: a.SecretHash,
+		Identity:   a.Identity,
+		Override:   a.AdoptionAllowedBy != "",
+	}
+	id, created, err = s.rancher.Reconcile(name, useExisting, a.Cluster, claim)
+	return id, name, created, err
+}
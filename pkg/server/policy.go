@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	"time"
+)
+
+// parsePolicy parses the action taken on agents no rule matches. allowPending
+// is set for actions that may leave the agent where it is.
+func parsePolicy(action string, def types.Status, allowPending bool) (types.Status, error) {
+	switch action {
+	case "":
+		return def, nil
+	case "pending":
+		if allowPending {
+			return types.StatusPending, nil
+		}
+	case "hold":
+		return types.StatusHeld, nil
+	case "deny":
+		return types.StatusDenied, nil
+	}
+
+	return "", fmt.Errorf("invalid action %s", action)
+}
+
+// applyPendingTimeout moves an agent no rule matched out of pending once it has
+// waited longer than the pending timeout at now. until then the agent is
+// enqueued again for when the timeout expires.
+func (s *Server) applyPendingTimeout(a *types.Agent, message string, now time.Time) error {
+	if s.config.PendingTimeout <= 0 {
+		return s.setPendingMessage(a.ID, message)
+	}
+
+	since := a.PendingSince
+	if since.IsZero() {
+		since = a.LastContact
+	}
+
+	timeout := time.Duration(s.config.PendingTimeout) * time.Second
+	waited := now.Sub(since)
+	if waited < timeout {
+		s.queue.AddAfter(a.ID, timeout-waited)
+		return s.setPendingMessage(a.ID, fmt.Sprintf("%s, pending until %s", message, since.Add(timeout).Format(time.RFC3339)))
+	}
+
+	return s.transitionAgent(a.ID, types.StatusPending, s.timeoutStatus, fmt.Sprintf("%s, no rule matched within %s", s.timeoutStatus, timeout))
+}
+
+// setPendingMessage records why an agent is still pending, without writing
+// to the store if nothing changed
+func (s *Server) setPendingMessage(id string, message string) error {
+	_, err := s.agentStore.UpdateAgentFunc(id, func(a *types.Agent) bool {
+		if a.Status != types.StatusPending || a.StatusMessage == message {
+			return false
+		}
+		a.StatusMessage = message
+		return true
+	})
+
+	return err
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"github.com/ebauman/moo/pkg/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateSecret(ctx context.Context, req *rpc.SecretRequest) (*rpc.Secret, error) {
+	secret, value, err := s.secretStore.CreateSecret(req.Description)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating secret: %v", err)
+	}
+
+	s.log.Infof("%s created shared secret %s", callerFromContext(ctx).Name, secret.ID)
+
+	resp := secretToRPC(secret)
+	resp.Secret = value
+
+	return resp, nil
+}
+
+func (s *Server) ListSecrets(ctx context.Context, e *rpc.Empty) (*rpc.SecretList, error) {
+	secrets := s.secretStore.ListSecrets()
+
+	converted := make([]*rpc.Secret, len(secrets))
+	for i, v := range secrets {
+		converted[i] = secretToRPC(v)
+	}
+
+	return &rpc.SecretList{Secrets: converted}, nil
+}
+
+func (s *Server) DeleteSecret(ctx context.Context, id *rpc.SecretID) (*rpc.DeleteResponse, error) {
+	resp, err := s.secretStore.DeleteSecret(id.GetID())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error deleting secret: %v", err)
+	}
+
+	if resp {
+		s.log.Infof("%s revoked shared secret %s", callerFromContext(ctx).Name, id.GetID())
+		// agents may now fall through to other rules
+		s.enqueueUndecided()
+	}
+
+	return &rpc.DeleteResponse{Success: resp}, nil
+}
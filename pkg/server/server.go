@@ -5,111 +5,161 @@ import (
 	"fmt"
 	"github.com/ebauman/moo/pkg/agentstore"
 	"github.com/ebauman/moo/pkg/config"
+	"github.com/ebauman/moo/pkg/keys"
+	"github.com/ebauman/moo/pkg/netutil"
 	"github.com/ebauman/moo/pkg/rancher"
 	"github.com/ebauman/moo/pkg/rpc"
 	"github.com/ebauman/moo/pkg/rulestore"
+	"github.com/ebauman/moo/pkg/secretstore"
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/ebauman/moo/pkg/tokenstore"
 	"github.com/ebauman/moo/pkg/types"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
-	"regexp"
-	"sync"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
+	"net"
+	"text/template"
 	"time"
 )
 
 type Server struct {
-	config     *config.ServerConfig
-	rancher    *rancher.RancherServer
-	agentStore *agentstore.Store
-	ruleStore  *rulestore.Store
-	log        *log.Logger
+	config      *config.ServerConfig
+	rancher     *rancher.RancherServer
+	agentStore  *agentstore.Store
+	ruleStore   *rulestore.Store
+	tokenStore  *tokenstore.Store
+	secretStore *secretstore.Store
+	queue       workqueue.RateLimitingInterface
+	log         *log.Logger
+
+	trustedProxies []*net.IPNet
+
+	// what happens to agents no rule matches
+	defaultStatus types.Status
+	timeoutStatus types.Status
+
+	// how the rancher clusters of accepted agents are named
+	nameTemplate *template.Template
+	collision    string
 }
 
-func NewServer(config *config.ServerConfig, rancher *rancher.RancherServer, log *log.Logger, rpcServ *grpc.Server) *Server {
-	agentStore := agentstore.NewStore()
-	ruleStore := rulestore.NewStore()
+func NewServer(config *config.ServerConfig, rancher *rancher.RancherServer, backend storage.Backend, log *log.Logger) (*Server, error) {
+	agentStore := agentstore.NewStore(backend)
+	ruleStore, err := rulestore.NewStore(backend, log)
+	if err != nil {
+		return nil, err
+	}
+	tokenStore := tokenstore.NewStore(backend)
 	serv := &Server{
-		config:     config,
-		rancher:    rancher,
-		agentStore: agentStore,
-		ruleStore:  ruleStore,
-		log:        log,
+		config:      config,
+		rancher:     rancher,
+		agentStore:  agentStore,
+		ruleStore:   ruleStore,
+		tokenStore:  tokenStore,
+		secretStore: secretstore.NewStore(backend),
+		queue:       workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "agents"),
+		log:         log,
+
+		trustedProxies: mustParseTrustedProxies(config.TrustedProxies, log),
 	}
-	rpc.RegisterMooServer(rpcServ, serv)
-	rpc.RegisterRulesServer(rpcServ, serv)
 
-	return serv
-}
+	if serv.defaultStatus, err = parsePolicy(config.DefaultAction, types.StatusPending, true); err != nil {
+		return nil, fmt.Errorf("default action: %v", err)
+	}
+	if serv.timeoutStatus, err = parsePolicy(config.PendingTimeoutAction, types.StatusDenied, false); err != nil {
+		return nil, fmt.Errorf("pending timeout action: %v", err)
+	}
+	if serv.nameTemplate, err = parseNameTemplate(config.ClusterNameTemplate); err != nil {
+		return nil, fmt.Errorf("cluster name template: %v", err)
+	}
+	if serv.collision, err = parseCollision(config.NameCollision); err != nil {
+		return nil, fmt.Errorf("name collision: %v", err)
+	}
 
-func (s *Server) Run(wg *sync.WaitGroup) {
-	defer wg.Done()
+	return serv, nil
+}
 
-	for {
-		s.applyRules()
-		s.registerClusters()
+// Register adds the moo services to an rpc server. the server should be built
+// with AuthorizeUnary and AuthorizeStream as interceptors.
+func (s *Server) Register(rpcServ *grpc.Server) {
+	rpc.RegisterMooServer(rpcServ, s)
+	rpc.RegisterRulesServer(rpcServ, s)
+	rpc.RegisterTokensServer(rpcServ, s)
+	rpc.RegisterSecretsServer(rpcServ, s)
+}
 
-		time.Sleep(time.Second * 30) // TODO - make this configurable
+// applyRules evaluates the rules in order against a pending agent and applies
+// the action of the first one that matches, or the default action if none do
+func (s *Server) applyRules(a *types.Agent, rules []rulestore.CompiledRule) error {
+	now := time.Now()
+	d := s.evaluateRules(a, rules, now, false)
+	if d.status == types.StatusPending {
+		return s.applyPendingTimeout(a, d.message, now)
 	}
+
+	s.log.Tracef("updating agent %s status to %s: %s", a.ID, d.status, d.message)
+	return s.applyDecision(a.ID, types.StatusPending, d, now)
 }
 
-// accepted clusters shall be registered
-func (s *Server) registerClusters() {
-	accepted := s.agentStore.ListAgentsByStatus(types.StatusAccepted)
-	for _, v := range accepted {
-		err := s.registerAgent(v)
-		if err != nil {
-			v.StatusMessage = fmt.Sprintf("error registering agent: %v", err)
-			v.Status = types.StatusError
-			s.agentStore.UpdateAgent(v)
-			continue
-		}
+// reapplyRules evaluates the rules against a held agent, which moves on if a
+// rule now matches with a different action. the default action does not
+// apply, a held agent stays held until a rule or an admin says otherwise.
+// agents held by an admin are left alone.
+func (s *Server) reapplyRules(a *types.Agent, rules []rulestore.CompiledRule) error {
+	if a.DecidedBy != "" {
+		return nil
+	}
+
+	now := time.Now()
+	d := s.evaluateRules(a, rules, now, false)
+	if d.rule == nil || d.status == types.StatusHeld {
+		return nil
 	}
+
+	s.log.Debugf("held agent %s now %s: %s", a.ID, d.status, d.message)
+	return s.applyDecision(a.ID, types.StatusHeld, d, now)
 }
 
-func (s *Server) applyRules() {
-	s.log.Tracef("applying rules")
-	// go through all the pending agents and apply rules accordingly
-	pending := s.agentStore.ListAgentsByStatus(types.StatusPending)
-	s.log.Tracef("listed pending agents, quantity %d", len(pending))
-	rules := s.ruleStore.ListRules()
-	s.log.Tracef("listed rules, quantity %d", len(rules))
+// evalRule evaluates each of a compiled rule's matchers and combines the
+// results. a rule that isn't Compound has a single matcher. the result of
+// each matcher, with Not applied, is returned too.
+func (s *Server) evalRule(a *types.Agent, r rulestore.CompiledRule) (bool, []bool) {
+	s.log.Tracef("evaluating rule (type: %s) (action: %s) (priority: %d) (regex: %s) for agent id %s", r.Type, r.Action, r.Priority, r.Regex, a.ID)
+	if r.Err != nil || len(r.Matchers) == 0 {
+		return false, nil
+	}
 
-	for _, a := range pending {
-		if len(rules) < 0 {
-			a.StatusMessage = fmt.Sprintf("held, no rules to evaluate")
-			s.agentStore.UpdateAgent(a)
-		}
-		for i, r := range rules {
-			// if rule applies, then perform action
-			if s.evalRule(a, r) {
-				s.log.Tracef("rule match found, updating agent status to %s", r.Action)
-				switch r.Action{
-				case types.Accept:
-					a.Status = types.StatusAccepted
-				case types.Hold:
-					a.Status = types.StatusHeld
-				case types.Deny:
-					a.Status = types.StatusDenied
-				}
-				a.StatusMessage = fmt.Sprintf("%s per rule index %d (type: %s)", a.Status, i, r.Type)
-				s.agentStore.UpdateAgent(a)
-				break
-			}
-		}
+	results := make([]bool, len(r.Matchers))
+	some, every := false, true
+	for i, m := range r.Matchers {
+		results[i] = s.match(a, m) != m.Not
+		some = some || results[i]
+		every = every && results[i]
+	}
+
+	if r.Operator == types.Or {
+		return some, results
 	}
 
-	s.log.Tracef("finished applying rules")
+	return every, results
 }
 
-func (s *Server) evalRule(a *types.Agent, r types.Rule) bool {
-	s.log.Tracef("evaluating rule (type: %s) (action: %s) (priority: %d) (regex: %s) for agent id %s", r.Type, r.Action, r.Priority, r.Regex, a.ID)
-	regex := regexp.MustCompile(r.Regex)
-	switch r.Type {
+func (s *Server) match(a *types.Agent, m rulestore.Matcher) bool {
+	switch m.Type {
 	case types.SharedSecret:
-		return regex.Match([]byte(a.Secret))
+		return s.matchSecret(a, m.SecretIDs)
 	case types.SourceIP:
-		return regex.Match([]byte(a.IP))
+		if len(m.Nets) > 0 {
+			return netutil.Contains(m.Nets, a.IP)
+		}
+		return m.Pattern.MatchString(a.IP)
 	case types.ClusterName:
-		return regex.Match([]byte(a.ClusterName))
+		return m.Pattern.MatchString(a.ClusterName)
+	case types.Labels:
+		return m.LabelSelector.Matches(labels.Set(a.Labels))
 	case types.All:
 		return true
 	}
@@ -117,23 +167,181 @@ func (s *Server) evalRule(a *types.Agent, r types.Rule) bool {
 	return false
 }
 
+// matchSecret checks the agent's secret hash against the stored secrets. when
+// ids are given only those secrets match, otherwise any stored secret will do.
+func (s *Server) matchSecret(a *types.Agent, ids []string) bool {
+	secret := s.secretStore.Match(a.SecretHash)
+	if secret == nil {
+		return false
+	}
+
+	if len(ids) == 0 {
+		return true
+	}
+	for _, id := range ids {
+		if id == secret.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *Server) registerAgent(a *types.Agent) error {
-	manifest, err := s.rancher.ReconcileToURL(a.ClusterName, a.UseExisting)
+	if a.RancherClusterID == "" {
+		if err := s.createCluster(a); err != nil {
+			return err
+		}
+	}
+	message := withConfigError("agent accepted", a.ClusterConfigError)
+
+	manifest, err := s.rancher.GetManifestURLForCluster(a.RancherClusterID)
 	if err != nil {
 		return err
 	}
 
-	a.ManifestUrl = manifest
-	a.Status = types.StatusAccepted
-	a.StatusMessage = "agent accepted"
-	s.agentStore.UpdateAgent(a)
+	_, err = s.agentStore.UpdateAgentFunc(a.ID, func(current *types.Agent) bool {
+		// the agent may have been decided again, or registered again with other
+		// credentials, while we were talking to rancher
+		if current.Status != types.StatusAccepted || current.RancherClusterID != a.RancherClusterID {
+			return false
+		}
+		current.ManifestUrl = manifest
+		current.StatusMessage = message
+		return true
+	})
+
+	return err
+}
+
+// createCluster creates or adopts the rancher cluster of an agent and records
+// it on the agent straight away, so that a retry after a later failure reuses
+// the cluster instead of colliding with it
+func (s *Server) createCluster(a *types.Agent) error {
+	clusterID, name, created, err := s.reconcileCluster(a)
+	if err != nil {
+		return err
+	}
+
+	// the cluster is usable without its projects and members, so failing to
+	// create them doesn't fail the registration. it is retried while the
+	// cluster is tracked.
+	configErr := ""
+	if created {
+		if err := s.rancher.ConfigureCluster(clusterID, a.Cluster); err != nil {
+			s.log.Errorf("error configuring cluster %s for agent %s: %v", clusterID, a.ID, err)
+			configErr = err.Error()
+		}
+	}
+
+	a.RancherClusterID = clusterID
+	a.RancherClusterName = name
+	a.ClusterConfigError = configErr
+	_, err = s.agentStore.UpdateAgentFunc(a.ID, func(current *types.Agent) bool {
+		current.RancherClusterID = clusterID
+		current.RancherClusterName = name
+		current.ClusterConfigError = configErr
+		return true
+	})
 
-	return nil
+	return err
+}
+
+// withConfigError adds the error configuring an agent's cluster, if any, to a
+// status message
+func withConfigError(message string, configErr string) string {
+	if configErr == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s, error configuring cluster: %s", message, configErr)
+}
+
+// transitionAgent moves an agent to a new status, but only if it is still in the
+// status it was in when the decision was made. agents are read and decided on
+// without holding the store lock, so anything may have happened in between.
+func (s *Server) transitionAgent(id string, from types.Status, to types.Status, message string) error {
+	return s.transitionAgentFunc(id, from, to, message, nil)
+}
+
+// transitionAgentFunc is transitionAgent, additionally calling fn on the agent
+// if it is moved. if fn returns false the agent is left as it was.
+func (s *Server) transitionAgentFunc(id string, from types.Status, to types.Status, message string, fn func(a *types.Agent) bool) error {
+	_, err := s.agentStore.UpdateAgentFunc(id, func(a *types.Agent) bool {
+		if a.Status != from {
+			s.log.Debugf("agent %s moved from %s to %s, not updating to %s", id, from, a.Status, to)
+			return false
+		}
+		a.Status = to
+		a.StatusMessage = message
+		a.DecidedBy = ""
+		a.AdoptionAllowedBy = ""
+		if to == types.StatusPending {
+			a.PendingSince = time.Now()
+		}
+		if fn != nil {
+			return fn(a)
+		}
+		return true
+	})
+
+	return err
+}
+
+// applyDecision moves an agent to the status the rules decided, recording the
+// rule that decided and the cluster settings it carries. the match counts
+// against the rule's quota only if the agent is actually moved. it is
+// recorded while the agent is locked, so the agent can't be counted twice.
+func (s *Server) applyDecision(id string, from types.Status, d decision, now time.Time) error {
+	var recordErr error
+	err := s.transitionAgentFunc(id, from, d.status, d.message, func(a *types.Agent) bool {
+		if recordErr = s.recordMatch(d, now); recordErr != nil {
+			return false
+		}
+		a.RuleID = ""
+		a.Cluster = nil
+		if d.rule != nil {
+			a.RuleID = d.rule.ID
+			a.Cluster = d.rule.Cluster
+		}
+		return true
+	})
+	if recordErr != nil {
+		return recordErr
+	}
+
+	return err
 }
 
 func (s *Server) GetAgentStatus(ctx context.Context, id *rpc.AgentID) (*rpc.StatusResponse, error) {
-	agent := s.agentStore.GetAgent(id.GetID())
+	return s.statusResponse(s.agentStore.GetAgent(id.GetID())), nil
+}
+
+// WatchAgentStatus sends the agent's current status, then a new status every
+// time it changes, until the client goes away
+func (s *Server) WatchAgentStatus(id *rpc.AgentID, stream rpc.Moo_WatchAgentStatusServer) error {
+	updates, cancel := s.agentStore.Watch(id.GetID())
+	defer cancel()
 
+	var last *rpc.StatusResponse
+	for {
+		resp := s.statusResponse(s.agentStore.GetAgent(id.GetID()))
+		if last == nil || resp.Status != last.Status || resp.Message != last.Message || resp.ManifestURL != last.ManifestURL {
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			last = resp
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-updates:
+		}
+	}
+}
+
+func (s *Server) statusResponse(agent *types.Agent) *rpc.StatusResponse {
 	resp := &rpc.StatusResponse{}
 
 	if agent == nil {
@@ -142,32 +350,86 @@ func (s *Server) GetAgentStatus(ctx context.Context, id *rpc.AgentID) (*rpc.Stat
 	} else {
 		resp.Status = statusToRPC(agent.Status)
 		resp.Message = agent.StatusMessage
+		if agent.Status == types.StatusAccepted {
+			resp.ManifestURL = agent.ManifestUrl
+		}
 	}
 
-	resp.ErrorTime = s.config.ErrorTime
+	resp.HoldTime = s.config.HoldTime
 	resp.PendingTime = s.config.PendingTime
 	resp.ErrorTime = s.config.ErrorTime
 
-	return resp, nil
+	return resp
 }
 
 func (s *Server) RegisterAgent(ctx context.Context, a *rpc.Agent) (*rpc.RegisterResponse, error) {
+	if err := validateLabels(a.GetLabels()); err != nil {
+		return nil, err
+	}
+
 	agent := &types.Agent{
-		ID:          a.GetID(),
-		Secret:      a.GetSecret(),
-		IP:          a.GetIP(),
-		Completed:   false,
-		LastContact: time.Now(), // now is when we last saw this agent
-		ClusterName: a.GetClusterName(),
-		UseExisting: a.GetUseExisting(),
-		Status:      types.StatusPending, // initial status is pending
+		ID:           a.GetID(),
+		SecretHash:   keys.Hash(a.GetSecret()), // only the hash is kept
+		IP:           s.sourceIP(ctx),          // never trust a client supplied address
+		Completed:    false,
+		LastContact:  time.Now(), // now is when we last saw this agent
+		PendingSince: time.Now(),
+		ClusterName:  a.GetClusterName(),
+		UseExisting:  a.GetUseExisting(),
+		Labels:       a.GetLabels(),
+		Status:       types.StatusPending, // initial status is pending
+		Identity:     peerIdentity(ctx),   // never trust a client supplied identity
+	}
+
+	// an agent registering again, e.g. after a restart, keeps its status and
+	// whoever decided it, so registering can't be used to escape a decision.
+	// only how it was reached is refreshed.
+	existing, err := s.agentStore.UpdateAgentFunc(agent.ID, func(existing *types.Agent) bool {
+		// the rancher cluster is only kept for the agent that was given it.
+		// anyone else has to go through the adoption checks like any agent
+		// wanting an existing cluster.
+		if !sameAgent(existing, agent) && existing.RancherClusterID != "" {
+			existing.RancherClusterID = ""
+			existing.RancherClusterName = ""
+			existing.ClusterConfigError = ""
+			existing.ManifestUrl = ""
+			if existing.Status == types.StatusAccepted || existing.Status == types.StatusRegistered {
+				existing.Status = types.StatusAccepted
+				existing.StatusMessage = "agent registered again with other credentials"
+				existing.Completed = false
+			}
+		}
+
+		existing.SecretHash = agent.SecretHash
+		existing.Identity = agent.Identity
+		existing.IP = agent.IP
+		existing.Labels = agent.Labels
+		existing.LastContact = agent.LastContact
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// we don't actually perform registration here, just add
+	if existing == nil {
+		if err := s.agentStore.AddAgent(agent); err != nil {
+			return nil, err
+		}
 	}
 
-	s.agentStore.AddAgent(agent) // we don't actually perform registration here, just add
+	s.enqueueAgent(agent.ID)
 
 	return &rpc.RegisterResponse{Success: true}, nil
 }
 
+// sameAgent reports whether a registration comes from the agent that
+// registered before, with the same identity and secret
+func sameAgent(existing *types.Agent, a *types.Agent) bool {
+	return existing.Identity == a.Identity &&
+		existing.SecretHash == a.SecretHash
+}
+
 func (s *Server) GetManifestURL(ctx context.Context, id *rpc.AgentID) (*rpc.ManifestResponse, error) {
 	agent := s.agentStore.GetAgent(id.GetID())
 	resp := &rpc.ManifestResponse{}
@@ -197,26 +459,97 @@ func (s *Server) ListAgents(ctx context.Context, request *rpc.ListRequest) (*rpc
 	return &rpc.AgentListResponse{Agents: convertedAgents}, nil
 }
 
-func (s *Server) DeleteRule(ctx context.Context, ri *rpc.RuleIndex) (*rpc.DeleteResponse, error) {
-	index := int(ri.Index)
+func (s *Server) DeleteRule(ctx context.Context, id *rpc.RuleID) (*rpc.DeleteResponse, error) {
+	resp, err := s.ruleStore.DeleteRule(id.GetID(), id.GetResourceVersion())
+	if err != nil {
+		return nil, ruleStoreError(err)
+	}
+
+	if resp {
+		s.log.Infof("%s deleted rule %s", callerFromContext(ctx).Name, id.GetID())
+	}
 
-	resp := s.ruleStore.DeleteRule(index)
+	s.enqueueUndecided()
 
 	return &rpc.DeleteResponse{Success: resp}, nil
 }
 
 func (s *Server) AddRule(ctx context.Context, r *rpc.Rule) (*rpc.AddResponse, error) {
-	rule := ruleToRPC(r)
+	rule, err := ruleToRPC(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRule(&rule); err != nil {
+		return nil, err
+	}
+
+	added, err := s.ruleStore.AddRule(rule)
+	if err != nil {
+		return nil, ruleStoreError(err)
+	}
+
+	s.log.Infof("%s added rule %s", callerFromContext(ctx).Name, added.ID)
+
+	s.enqueueUndecided()
+
+	return &rpc.AddResponse{Success: true, ID: added.ID}, nil
+}
+
+func (s *Server) GetRule(ctx context.Context, id *rpc.RuleID) (*rpc.Rule, error) {
+	rule, ok := s.ruleStore.GetRule(id.GetID())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "rule %s not found", id.GetID())
+	}
 
-	resp := s.ruleStore.AddRule(rule)
+	return compiledRuleToRpc(compileRule(rule), time.Now()), nil
+}
+
+// UpdateRule replaces a rule. the resource version sent must be the one last
+// read, so an update made in the meantime isn't silently overwritten.
+func (s *Server) UpdateRule(ctx context.Context, r *rpc.Rule) (*rpc.Rule, error) {
+	rule, err := ruleToRPC(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if rule.ID == "" {
+		return nil, status.Error(codes.InvalidArgument, "rule id is required")
+	}
+	if err := validateRule(&rule); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.ruleStore.UpdateRule(rule)
+	if err != nil {
+		return nil, ruleStoreError(err)
+	}
+
+	s.log.Infof("%s updated rule %s to version %d", callerFromContext(ctx).Name, updated.ID, updated.ResourceVersion)
+
+	s.enqueueUndecided()
 
-	return &rpc.AddResponse{Success: resp}, nil
+	return compiledRuleToRpc(compileRule(updated), time.Now()), nil
 }
 
 func (s *Server) ListRules(ctx context.Context, e *rpc.Empty) (*rpc.RuleList, error) {
-	ruleList := &rpc.RuleList{
-		Rules: convertRuleSlice(s.ruleStore.ListRules()),
+	ruleList := &rpc.RuleList{}
+
+	now := time.Now()
+	for _, c := range s.ruleStore.CompiledRules() {
+		ruleList.Rules = append(ruleList.Rules, compiledRuleToRpc(c, now))
 	}
 
 	return ruleList, nil
+}
+
+func ruleStoreError(err error) error {
+	switch err {
+	case rulestore.ErrNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case rulestore.ErrConflict:
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "error storing rule: %v", err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,562 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/ebauman/moo/pkg/config"
+	"github.com/ebauman/moo/pkg/keys"
+	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/ebauman/moo/pkg/rulestore"
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) *Server {
+	return newTestServerWithBackend(t, storage.NewMemoryBackend())
+}
+
+func newTestServerWithBackend(t *testing.T, backend storage.Backend) *Server {
+	return newTestServerWithConfig(t, &config.ServerConfig{}, backend)
+}
+
+func newTestServerWithConfig(t *testing.T, cfg *config.ServerConfig, backend storage.Backend) *Server {
+	logger := log.New()
+	logger.SetLevel(log.PanicLevel)
+
+	s, err := NewServer(cfg, nil, backend, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// run with -race. hammers the rpc handlers while the reconcile workers run.
+func TestConcurrentRPCAndReconcile(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	const workers = 8
+	const iterations = 200
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	// reconcile workers plus a resync that never lets up
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runWorker()
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				s.enqueueAll()
+			}
+		}
+	}()
+
+	var clients sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		clients.Add(1)
+		go func(w int) {
+			defer clients.Done()
+			for i := 0; i < iterations; i++ {
+				id := fmt.Sprintf("agent-%d-%d", w, i)
+				_, err := s.RegisterAgent(ctx, &rpc.Agent{ID: id, ClusterName: fmt.Sprintf("edge-%d", i)})
+				if err != nil {
+					t.Errorf("RegisterAgent: %v", err)
+					return
+				}
+
+				if _, err := s.GetAgentStatus(ctx, &rpc.AgentID{ID: id}); err != nil {
+					t.Errorf("GetAgentStatus: %v", err)
+				}
+				if _, err := s.GetManifestURL(ctx, &rpc.AgentID{ID: id}); err != nil {
+					t.Errorf("GetManifestURL: %v", err)
+				}
+				if _, err := s.ListAgents(ctx, &rpc.ListRequest{Status: rpc.Status_Pending}); err != nil {
+					t.Errorf("ListAgents: %v", err)
+				}
+
+				switch i % 10 {
+				case 0:
+					s.AddRule(ctx, &rpc.Rule{Type: rpc.RuleType_ClusterName, Action: rpc.RuleAction_Hold, Priority: int32(i), Regex: "^edge-"})
+				case 5:
+					if rules := s.ruleStore.ListRules(); len(rules) > 0 {
+						s.DeleteRule(ctx, &rpc.RuleID{ID: rules[0].ID})
+					}
+				}
+
+				if _, err := s.ListRules(ctx, &rpc.Empty{}); err != nil {
+					t.Errorf("ListRules: %v", err)
+				}
+			}
+		}(w)
+	}
+
+	clients.Wait()
+	close(done)
+	s.queue.ShutDown()
+	wg.Wait()
+
+	if got := len(s.agentStore.ListAgents()); got != workers*iterations {
+		t.Fatalf("expected %d agents, got %d", workers*iterations, got)
+	}
+}
+
+func TestApplyRulesDoesNotClobberConcurrentUpdate(t *testing.T) {
+	s := newTestServer(t)
+
+	if _, err := s.ruleStore.AddRule(types.Rule{Type: types.All, Action: types.Deny}); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &types.Agent{ID: "a", Status: types.StatusPending}
+	if err := s.agentStore.AddAgent(a); err != nil {
+		t.Fatal(err)
+	}
+
+	// the agent was decided on while pending, but has since been accepted
+	if _, err := s.agentStore.UpdateAgentFunc("a", func(a *types.Agent) bool {
+		a.Status = types.StatusAccepted
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.transitionAgent("a", types.StatusPending, types.StatusDenied, "denied")
+
+	if got := s.agentStore.GetAgent("a").Status; got != types.StatusAccepted {
+		t.Fatalf("expected agent to stay %s, got %s", types.StatusAccepted, got)
+	}
+}
+
+func TestStoreReturnsCopies(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.PanicLevel)
+
+	backends := []struct {
+		name    string
+		backend func(t *testing.T) storage.Backend
+	}{
+		{"memory", func(t *testing.T) storage.Backend {
+			return storage.NewMemoryBackend()
+		}},
+		{"file", func(t *testing.T) storage.Backend {
+			dir, err := ioutil.TempDir("", "moo-server")
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { os.RemoveAll(dir) })
+
+			f, err := storage.NewFileBackend(filepath.Join(dir, "moo.db"), logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { f.Close() })
+			return f
+		}},
+	}
+
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			s := newTestServerWithBackend(t, b.backend(t))
+			agent := &types.Agent{
+				ID:      "a",
+				Status:  types.StatusPending,
+				Labels:  map[string]string{"env": "edge"},
+				Cluster: &types.ClusterSettings{Labels: map[string]string{"team": "a"}, Projects: []types.ClusterProject{{Name: "apps"}}},
+			}
+			if err := s.agentStore.AddAgent(agent); err != nil {
+				t.Fatal(err)
+			}
+			// nor does the agent that was added
+			agent.Labels["env"] = "added"
+
+			a := s.agentStore.GetAgent("a")
+			a.Status = types.StatusAccepted
+			a.Labels["env"] = "get"
+			a.Cluster.Labels["team"] = "get"
+			a.Cluster.Projects[0].Name = "get"
+			s.agentStore.ListAgents()[0].Labels["env"] = "list"
+			updated, err := s.agentStore.UpdateAgentFunc("a", func(a *types.Agent) bool {
+				a.LastContact = time.Now()
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated.Labels["env"] = "update"
+
+			a = s.agentStore.GetAgent("a")
+			if a.Status != types.StatusPending || a.Labels["env"] != "edge" || a.Cluster.Labels["team"] != "a" || a.Cluster.Projects[0].Name != "apps" {
+				t.Fatalf("modifying a returned agent changed the store: got %+v, %+v", a, a.Cluster)
+			}
+
+			notAfter := time.Now().Add(time.Hour)
+			if _, err := s.ruleStore.AddRule(types.Rule{
+				Type:       types.Compound,
+				Action:     types.Hold,
+				Operator:   types.And,
+				Conditions: []types.Condition{{Type: types.SourceIP, CIDRs: []string{"10.0.0.0/8"}}},
+				SecretIDs:  []string{"s"},
+				CIDRs:      []string{"10.0.0.0/8"},
+				Windows:    []types.Window{{Schedule: "0 9 * * *", Duration: "1h"}},
+				NotAfter:   &notAfter,
+				Cluster:    &types.ClusterSettings{Annotations: map[string]string{"a": "b"}, Members: []types.ClusterMember{{UserID: "u", Role: "cluster-owner"}}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			rules := s.ruleStore.ListRules()
+			rules[0].Action = types.Accept
+			rules[0].Conditions[0].CIDRs[0] = "0.0.0.0/0"
+			rules[0].SecretIDs[0] = "other"
+			rules[0].CIDRs[0] = "0.0.0.0/0"
+			rules[0].Windows[0].Schedule = "* * * * *"
+			*rules[0].NotAfter = time.Time{}
+			rules[0].Cluster.Annotations["a"] = "c"
+			rules[0].Cluster.Members[0].Role = "cluster-member"
+
+			r := s.ruleStore.ListRules()[0]
+			switch {
+			case r.Action != types.Hold,
+				r.Conditions[0].CIDRs[0] != "10.0.0.0/8",
+				r.SecretIDs[0] != "s",
+				r.CIDRs[0] != "10.0.0.0/8",
+				r.Windows[0].Schedule != "0 9 * * *",
+				!r.NotAfter.Equal(notAfter),
+				r.Cluster.Annotations["a"] != "b",
+				r.Cluster.Members[0].Role != "cluster-owner":
+				t.Fatalf("modifying a returned rule changed the store: got %+v", r)
+			}
+		})
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	statuses := []types.Status{
+		types.StatusUnknown,
+		types.StatusAccepted,
+		types.StatusHeld,
+		types.StatusDenied,
+		types.StatusPending,
+		types.StatusError,
+		types.StatusRegistered,
+	}
+
+	for _, status := range statuses {
+		if got := statusFromRPC(statusToRPC(status)); got != status {
+			t.Errorf("%s came back as %s", status, got)
+		}
+	}
+}
+
+func TestRegisterAgentAgainKeepsDecision(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    types.Agent
+		secret      string
+		wantStatus  types.Status
+		wantCluster string
+	}{
+		{
+			name:       "denied by an admin",
+			existing:   types.Agent{Status: types.StatusDenied, DecidedBy: "admin"},
+			wantStatus: types.StatusDenied,
+		},
+		{
+			name:       "held by an admin",
+			existing:   types.Agent{Status: types.StatusHeld, DecidedBy: "admin"},
+			wantStatus: types.StatusHeld,
+		},
+		{
+			name:        "registered",
+			existing:    types.Agent{Status: types.StatusRegistered, Completed: true, RancherClusterID: "c-1"},
+			wantStatus:  types.StatusRegistered,
+			wantCluster: "c-1",
+		},
+		{
+			name:       "registered with another secret",
+			existing:   types.Agent{Status: types.StatusRegistered, Completed: true, RancherClusterID: "c-1", ManifestUrl: "url"},
+			secret:     "other",
+			wantStatus: types.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			existing := tt.existing
+			existing.ID = "a"
+			existing.ClusterName = "edge"
+			if err := s.agentStore.AddAgent(&existing); err != nil {
+				t.Fatal(err)
+			}
+
+			labels := map[string]string{"env": "edge"}
+			if _, err := s.RegisterAgent(context.Background(), &rpc.Agent{ID: "a", ClusterName: "edge", Secret: tt.secret, Labels: labels}); err != nil {
+				t.Fatal(err)
+			}
+
+			a := s.agentStore.GetAgent("a")
+			if a.Status != tt.wantStatus || a.DecidedBy != tt.existing.DecidedBy {
+				t.Fatalf("got status %s decided by %q, want %s decided by %q", a.Status, a.DecidedBy, tt.wantStatus, tt.existing.DecidedBy)
+			}
+			if a.RancherClusterID != tt.wantCluster {
+				t.Fatalf("got cluster %q, want %q", a.RancherClusterID, tt.wantCluster)
+			}
+			if a.Labels["env"] != "edge" {
+				t.Fatalf("labels were not refreshed: %v", a.Labels)
+			}
+		})
+	}
+}
+
+func TestSharedSecretRuleWithRegexNeverMatches(t *testing.T) {
+	backend := storage.NewMemoryBackend()
+	s := newTestServerWithBackend(t, backend)
+
+	secret, value, err := s.secretStore.CreateSecret("edge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &types.Agent{ID: "a", SecretHash: keys.Hash(value), Status: types.StatusPending}
+
+	// rules from the file or kubernetes backends aren't validated by AddRule
+	rules := []types.Rule{
+		{ID: "restricted", Type: types.SharedSecret, Action: types.Accept, Regex: "^nothing$"},
+		{ID: "any", Type: types.SharedSecret, Action: types.Accept, SecretIDs: []string{secret.ID}},
+	}
+	if err := backend.PutRules(rules); err != nil {
+		t.Fatal(err)
+	}
+
+	d := s.evaluateRules(a, s.ruleStore.CompiledRules(), time.Now(), true)
+	if d.results[0].rule.Err == nil || d.results[0].matched {
+		t.Fatalf("rule with a regex compiled or matched: %+v", d.results[0])
+	}
+	if d.rule == nil || d.rule.ID != "any" {
+		t.Fatalf("expected rule any to decide, got %+v", d.rule)
+	}
+}
+
+func TestInvalidRuleFromBackendIsSkipped(t *testing.T) {
+	backend := storage.NewMemoryBackend()
+	s := newTestServerWithBackend(t, backend)
+
+	rules := []types.Rule{
+		{ID: "typo", Type: types.All, Action: "Acept", Priority: 10, MaxMatches: 1},
+		{ID: "deny", Type: types.All, Action: types.Deny},
+	}
+	if err := backend.PutRules(rules); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.agentStore.AddAgent(&types.Agent{ID: "a", Status: types.StatusPending}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.reconcileAgent("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.agentStore.GetAgent("a").Status; got != types.StatusDenied {
+		t.Fatalf("got status %s, want %s", got, types.StatusDenied)
+	}
+	if r, _ := s.ruleStore.GetRule("typo"); r.Usage.Matches != 0 {
+		t.Fatalf("invalid rule used up %d matches", r.Usage.Matches)
+	}
+}
+
+func TestRuleOverQuota(t *testing.T) {
+	tests := []struct {
+		name          string
+		holdOverQuota bool
+		wantStatus    types.Status
+		wantRule      string
+	}{
+		{name: "falls through", wantStatus: types.StatusHeld, wantRule: "fallback"},
+		{name: "holds", holdOverQuota: true, wantStatus: types.StatusHeld, wantRule: "once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := storage.NewMemoryBackend()
+			s := newTestServerWithBackend(t, backend)
+
+			rules := []types.Rule{
+				{ID: "once", Type: types.All, Action: types.Deny, Priority: 10, MaxMatches: 1, HoldOverQuota: tt.holdOverQuota},
+				{ID: "fallback", Type: types.All, Action: types.Hold},
+			}
+			if err := backend.PutRules(rules); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range []string{"a", "b"} {
+				if err := s.agentStore.AddAgent(&types.Agent{ID: id, Status: types.StatusPending}); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.reconcileAgent(id); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if a := s.agentStore.GetAgent("a"); a.Status != types.StatusDenied || a.RuleID != "once" {
+				t.Fatalf("first agent: got %s per rule %q", a.Status, a.RuleID)
+			}
+			if b := s.agentStore.GetAgent("b"); b.Status != tt.wantStatus || b.RuleID != tt.wantRule {
+				t.Fatalf("second agent: got %s per rule %q, want %s per rule %q", b.Status, b.RuleID, tt.wantStatus, tt.wantRule)
+			}
+			// an agent held over quota doesn't count against it
+			if r, _ := s.ruleStore.GetRule("once"); r.Usage.Matches != 1 {
+				t.Fatalf("got %d matches, want 1", r.Usage.Matches)
+			}
+		})
+	}
+}
+
+func TestEvalCompoundRule(t *testing.T) {
+	a := &types.Agent{ID: "a", ClusterName: "edge-1", IP: "10.0.0.5", Labels: map[string]string{"env": "prod"}}
+
+	name := types.Condition{Type: types.ClusterName, Regex: "^edge-"}
+	otherName := types.Condition{Type: types.ClusterName, Regex: "^core-"}
+	ip := types.Condition{Type: types.SourceIP, CIDRs: []string{"10.0.0.0/8"}}
+	otherIP := types.Condition{Type: types.SourceIP, CIDRs: []string{"192.168.0.0/16"}}
+	prod := types.Condition{Type: types.Labels, Selector: "env=prod"}
+	not := func(c types.Condition) types.Condition {
+		c.Not = true
+		return c
+	}
+
+	tests := []struct {
+		name           string
+		operator       types.Operator
+		conditions     []types.Condition
+		wantMatched    bool
+		wantConditions []bool
+		wantErr        bool
+	}{
+		{name: "and", operator: types.And, conditions: []types.Condition{name, ip, prod}, wantMatched: true, wantConditions: []bool{true, true, true}},
+		{name: "and with one failing", operator: types.And, conditions: []types.Condition{name, otherIP}, wantConditions: []bool{true, false}},
+		{name: "and by default", conditions: []types.Condition{name, otherIP}, wantConditions: []bool{true, false}},
+		{name: "or", operator: types.Or, conditions: []types.Condition{otherName, ip}, wantMatched: true, wantConditions: []bool{false, true}},
+		{name: "or with none matching", operator: types.Or, conditions: []types.Condition{otherName, otherIP}, wantConditions: []bool{false, false}},
+		{name: "not", operator: types.And, conditions: []types.Condition{name, not(otherIP)}, wantMatched: true, wantConditions: []bool{true, true}},
+		{name: "not failing", operator: types.And, conditions: []types.Condition{name, not(ip)}, wantConditions: []bool{true, false}},
+		{name: "or of nots", operator: types.Or, conditions: []types.Condition{not(name), not(otherName)}, wantMatched: true, wantConditions: []bool{false, true}},
+		{name: "single condition", conditions: []types.Condition{prod}, wantMatched: true, wantConditions: []bool{true}},
+		{name: "nested", operator: types.Or, conditions: []types.Condition{name, {Type: types.Compound}}, wantErr: true},
+		{name: "no conditions", operator: types.And, wantErr: true},
+		{name: "condition without type", operator: types.Or, conditions: []types.Condition{name, {}}, wantErr: true},
+		{name: "invalid regex", operator: types.Or, conditions: []types.Condition{name, {Type: types.ClusterName, Regex: "("}}, wantErr: true},
+		{name: "invalid cidr", operator: types.Or, conditions: []types.Condition{name, {Type: types.SourceIP, CIDRs: []string{"nope"}}}, wantErr: true},
+		{name: "labels without selector", operator: types.Or, conditions: []types.Condition{name, {Type: types.Labels}}, wantErr: true},
+		{name: "unknown operator", operator: "Xor", conditions: []types.Condition{name}, wantErr: true},
+	}
+
+	s := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := rulestore.Compile(types.Rule{ID: "r", Type: types.Compound, Action: types.Accept, Operator: tt.operator, Conditions: tt.conditions})
+			r.Err = err
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("got compile error %v, want error %v", err, tt.wantErr)
+			}
+
+			matched, conditions := s.evalRule(a, r)
+			if matched != tt.wantMatched {
+				t.Fatalf("got matched %v, want %v", matched, tt.wantMatched)
+			}
+			if fmt.Sprint(conditions) != fmt.Sprint(tt.wantConditions) {
+				t.Fatalf("got conditions %v, want %v", conditions, tt.wantConditions)
+			}
+		})
+	}
+}
+
+func TestApplyPendingTimeout(t *testing.T) {
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		action     string
+		agent      types.Agent
+		now        time.Time
+		wantStatus types.Status
+	}{
+		{
+			name:       "before the timeout",
+			agent:      types.Agent{Status: types.StatusPending, PendingSince: start},
+			now:        start.Add(59 * time.Second),
+			wantStatus: types.StatusPending,
+		},
+		{
+			name:       "denied by default",
+			agent:      types.Agent{Status: types.StatusPending, PendingSince: start},
+			now:        start.Add(time.Minute),
+			wantStatus: types.StatusDenied,
+		},
+		{
+			name:       "held",
+			action:     "hold",
+			agent:      types.Agent{Status: types.StatusPending, PendingSince: start},
+			now:        start.Add(time.Minute),
+			wantStatus: types.StatusHeld,
+		},
+		{
+			name:       "pending since last contact",
+			agent:      types.Agent{Status: types.StatusPending, LastContact: start},
+			now:        start.Add(time.Minute),
+			wantStatus: types.StatusDenied,
+		},
+		{
+			name:       "accepted",
+			agent:      types.Agent{Status: types.StatusAccepted, PendingSince: start},
+			now:        start.Add(time.Hour),
+			wantStatus: types.StatusAccepted,
+		},
+		{
+			name:       "held by an admin",
+			agent:      types.Agent{Status: types.StatusHeld, DecidedBy: "admin", PendingSince: start},
+			now:        start.Add(time.Hour),
+			wantStatus: types.StatusHeld,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServerWithConfig(t, &config.ServerConfig{PendingTimeout: 60, PendingTimeoutAction: tt.action}, storage.NewMemoryBackend())
+			defer s.queue.ShutDown()
+
+			a := tt.agent
+			a.ID = "a"
+			if err := s.agentStore.AddAgent(&a); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.applyPendingTimeout(&a, "pending, no rule matched", tt.now); err != nil {
+				t.Fatal(err)
+			}
+
+			got := s.agentStore.GetAgent("a")
+			if got.Status != tt.wantStatus {
+				t.Fatalf("got status %s, want %s", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
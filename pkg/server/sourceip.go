@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"github.com/ebauman/moo/pkg/netutil"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"strings"
+)
+
+// ParseTrustedProxies parses a list of ip addresses and cidr blocks
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	return netutil.ParseCIDRs(proxies)
+}
+
+func mustParseTrustedProxies(proxies []string, log *log.Logger) []*net.IPNet {
+	nets, err := ParseTrustedProxies(proxies)
+	if err != nil {
+		log.Errorf("error parsing trusted proxies, not trusting any: %v", err)
+		return nil
+	}
+
+	return nets
+}
+
+func (s *Server) trusted(ip net.IP) bool {
+	for _, n := range s.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeCIDRs validates the cidr blocks on a SourceIP rule and returns them
+// in canonical form
+func normalizeCIDRs(cidrs []string) ([]string, error) {
+	nets, err := netutil.ParseCIDRs(cidrs)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, len(nets))
+	for i, n := range nets {
+		normalized[i] = n.String()
+	}
+
+	return normalized, nil
+}
+
+// sourceIP is the address of the caller, taken from the connection rather than
+// anything the client says about itself. connections behind a proxy protocol
+// listener already carry the proxied address. if the caller is a trusted proxy,
+// x-forwarded-for is walked from the right and the first untrusted hop wins.
+func (s *Server) sourceIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !s.trusted(ip) {
+		return host
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return host
+	}
+
+	hops := make([]string, 0)
+	for _, v := range md.Get("x-forwarded-for") {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hopIP := net.ParseIP(hops[i])
+		if hopIP == nil {
+			// garbage from here on can't be trusted
+			break
+		}
+		host = hopIP.String()
+		if !s.trusted(hopIP) {
+			break
+		}
+	}
+
+	return host
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestSourceIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		peer    string
+		xff     []string
+		want    string
+	}{
+		{
+			name: "no proxies trusted",
+			peer: "203.0.113.7:4000",
+			xff:  []string{"198.51.100.1"},
+			want: "203.0.113.7",
+		},
+		{
+			name:    "untrusted peer",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "203.0.113.7:4000",
+			xff:     []string{"198.51.100.1"},
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "trusted peer without header",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "10.0.0.1:4000",
+			want:    "10.0.0.1",
+		},
+		{
+			name:    "single hop",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "10.0.0.1:4000",
+			xff:     []string{"198.51.100.1"},
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "spoofed hops left of the first untrusted",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "10.0.0.1:4000",
+			xff:     []string{"1.1.1.1, 198.51.100.1, 10.0.0.2"},
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "hops across several headers",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "10.0.0.1:4000",
+			xff:     []string{"1.1.1.1, 198.51.100.1", "10.0.0.3,10.0.0.2"},
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "every hop trusted",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "10.0.0.1:4000",
+			xff:     []string{"10.0.0.3, 10.0.0.2"},
+			want:    "10.0.0.3",
+		},
+		{
+			name:    "garbage hop",
+			trusted: []string{"10.0.0.0/8"},
+			peer:    "10.0.0.1:4000",
+			xff:     []string{"198.51.100.1, unknown, 10.0.0.2"},
+			want:    "10.0.0.2",
+		},
+		{
+			name:    "ipv4 proxy written as ipv6",
+			trusted: []string{"::ffff:10.0.0.1"},
+			peer:    "10.0.0.1:4000",
+			xff:     []string{"198.51.100.1"},
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "ipv6 peer not trusted by an ipv4 proxy written as ipv6",
+			trusted: []string{"::ffff:10.0.0.1"},
+			peer:    "[::1]:4000",
+			xff:     []string{"198.51.100.1"},
+			want:    "::1",
+		},
+		{
+			name:    "ipv6",
+			trusted: []string{"fd00::/8", "10.0.0.1"},
+			peer:    "[fd00::1]:4000",
+			xff:     []string{"2001:db8::1, 10.0.0.1"},
+			want:    "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			nets, err := ParseTrustedProxies(tt.trusted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.trustedProxies = nets
+
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.xff != nil {
+				md := metadata.MD{}
+				md.Append("x-forwarded-for", tt.xff...)
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			if got := s.sourceIP(ctx); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCIDRs(t *testing.T) {
+	tests := []struct {
+		cidrs   []string
+		want    []string
+		wantErr bool
+	}{
+		{cidrs: []string{"10.1.2.3/8", "192.168.1.1"}, want: []string{"10.0.0.0/8", "192.168.1.1/32"}},
+		{cidrs: []string{"2001:DB8::1/32", "fd00::1"}, want: []string{"2001:db8::/32", "fd00::1/128"}},
+		{cidrs: []string{"::ffff:192.168.1.1"}, want: []string{"192.168.1.1/32"}},
+		{cidrs: []string{"10.0.0.0/8", "300.0.0.1"}, wantErr: true},
+		{cidrs: []string{"10.0.0.0/40"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeCIDRs(tt.cidrs)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%v: expected an error", tt.cidrs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", tt.cidrs, err)
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%v: got %v, want %v", tt.cidrs, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"github.com/ebauman/moo/pkg/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateToken(ctx context.Context, req *rpc.TokenRequest) (*rpc.Token, error) {
+	t, bearer, err := s.tokenStore.CreateToken(roleFromRPC(req.Role), req.Description)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating token: %v", err)
+	}
+
+	s.log.Infof("%s created %s token %s", callerFromContext(ctx).Name, t.Role, t.ID)
+
+	resp := tokenToRPC(t)
+	resp.Secret = bearer
+
+	return resp, nil
+}
+
+func (s *Server) ListTokens(ctx context.Context, e *rpc.Empty) (*rpc.TokenList, error) {
+	tokens := s.tokenStore.ListTokens()
+
+	converted := make([]*rpc.Token, len(tokens))
+	for i, t := range tokens {
+		converted[i] = tokenToRPC(t)
+	}
+
+	return &rpc.TokenList{Tokens: converted}, nil
+}
+
+func (s *Server) DeleteToken(ctx context.Context, id *rpc.TokenID) (*rpc.DeleteResponse, error) {
+	resp, err := s.tokenStore.DeleteToken(id.GetID())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error deleting token: %v", err)
+	}
+
+	if resp {
+		s.log.Infof("%s revoked token %s", callerFromContext(ctx).Name, id.GetID())
+	}
+
+	return &rpc.DeleteResponse{Success: resp}, nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/rancher"
+	"github.com/ebauman/moo/pkg/types"
+)
+
+// trackCluster follows the rancher cluster of an accepted agent that has been
+// handed its manifest, and retries configuring the cluster if that failed
+// before. once rancher reports the cluster active and it is configured the
+// agent is registered and completed, until then the state of the cluster is
+// kept in the status message. it is called on every resync.
+func (s *Server) trackCluster(a *types.Agent) error {
+	state, err := s.rancher.GetClusterState(a.RancherClusterID)
+	if rancher.IsNotFound(err) {
+		// forgotten, so approving the agent again creates a new cluster
+		return s.transitionAgentFunc(a.ID, types.StatusAccepted, types.StatusError, fmt.Sprintf("cluster %s no longer exists in rancher", a.RancherClusterName), func(a *types.Agent) bool {
+			a.RancherClusterID = ""
+			a.RancherClusterName = ""
+			a.ClusterConfigError = ""
+			a.ManifestUrl = ""
+			return true
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("error getting state of cluster %s: %v", a.RancherClusterID, err)
+	}
+
+	configErr := a.ClusterConfigError
+	if configErr != "" {
+		if err := s.rancher.ConfigureCluster(a.RancherClusterID, a.Cluster); err != nil {
+			s.log.Debugf("error configuring cluster %s for agent %s: %v", a.RancherClusterID, a.ID, err)
+			configErr = err.Error()
+		} else {
+			s.log.Infof("configured cluster %s for agent %s", a.RancherClusterID, a.ID)
+			configErr = ""
+		}
+	}
+
+	message := fmt.Sprintf("agent accepted, cluster %s in rancher", state.State)
+	if state.Message != "" {
+		message = fmt.Sprintf("%s (%s)", message, state.Message)
+	}
+	message = withConfigError(message, configErr)
+
+	_, err = s.agentStore.UpdateAgentFunc(a.ID, func(current *types.Agent) bool {
+		// the agent may have been re-registered or decided again meanwhile
+		if current.Status != types.StatusAccepted || current.RancherClusterID != a.RancherClusterID {
+			return false
+		}
+		if state.Active && configErr == "" {
+			s.log.Infof("cluster %s of agent %s is active in rancher", a.RancherClusterID, a.ID)
+			current.Status = types.StatusRegistered
+			current.StatusMessage = "cluster active in rancher"
+			current.ClusterConfigError = ""
+			current.Completed = true
+			return true
+		}
+		if current.StatusMessage == message && current.ClusterConfigError == configErr {
+			return false
+		}
+		current.StatusMessage = message
+		current.ClusterConfigError = configErr
+		return true
+	})
+
+	return err
+}
@@ -2,32 +2,96 @@ package server
 
 import (
 	"github.com/ebauman/moo/pkg/rpc"
+	"github.com/ebauman/moo/pkg/rulestore"
 	"github.com/ebauman/moo/pkg/types"
 	"time"
 )
 
-func convertRuleSlice(rules []types.Rule) []*rpc.Rule {
-	rpcRules := make([]*rpc.Rule, 0)
-	for _, r := range rules {
-		rpcRules = append(rpcRules, ruleToRpc(r))
-	}
-
-	return rpcRules
-}
-
 func ruleToRpc(rule types.Rule) *rpc.Rule {
 	rt := ruleTypeToRpc(rule.Type)
 	ra := ruleActionToRpc(rule.Action)
 	rpcRule := &rpc.Rule{
-		Type:     rt,
-		Action:   ra,
-		Priority: rule.Priority,
-		Regex:    rule.Regex,
+		ID:              rule.ID,
+		ResourceVersion: rule.ResourceVersion,
+
+		Type:      rt,
+		Action:    ra,
+		Priority:  rule.Priority,
+		Regex:     rule.Regex,
+		SecretIDs: rule.SecretIDs,
+		CIDRs:     rule.CIDRs,
+		Selector:  rule.Selector,
+
+		NotBefore: formatRuleTime(rule.NotBefore),
+		NotAfter:  formatRuleTime(rule.NotAfter),
+		TimeZone:  rule.TimeZone,
+
+		MaxMatches:    rule.MaxMatches,
+		RateLimit:     rule.RateLimit,
+		RatePeriod:    rule.RatePeriod,
+		HoldOverQuota: rule.HoldOverQuota,
+		Matches:       rule.Usage.Matches,
+
+		Cluster: clusterSettingsToRPC(rule.Cluster),
+	}
+
+	for _, w := range rule.Windows {
+		rpcRule.Windows = append(rpcRule.Windows, &rpc.Window{Schedule: w.Schedule, Duration: w.Duration})
+	}
+
+	if rule.Type == types.Compound {
+		rpcRule.Operator = operatorToRPC(rule.Operator)
+		rpcRule.Conditions = make([]*rpc.Condition, len(rule.Conditions))
+		for i, c := range rule.Conditions {
+			rpcRule.Conditions[i] = &rpc.Condition{
+				Type:      ruleTypeToRpc(c.Type),
+				Regex:     c.Regex,
+				SecretIDs: c.SecretIDs,
+				CIDRs:     c.CIDRs,
+				Selector:  c.Selector,
+				Not:       c.Not,
+			}
+		}
+	}
+
+	return rpcRule
+}
+
+// compiledRuleToRpc converts a rule along with whether it is active at now,
+// and how many agents it decided in its current rate period
+func compiledRuleToRpc(c rulestore.CompiledRule, now time.Time) *rpc.Rule {
+	rpcRule := ruleToRpc(c.Rule)
+	rpcRule.Active = c.Err == nil && c.Active(now)
+	if c.RateLimit > 0 {
+		rpcRule.RecentMatches = rulestore.RecentMatches(c.Usage, c.Period, now)
 	}
 
 	return rpcRule
 }
 
+func compileRule(r types.Rule) rulestore.CompiledRule {
+	c, err := rulestore.Compile(r)
+	c.Err = err
+
+	return c
+}
+
+func operatorToRPC(op types.Operator) rpc.Operator {
+	if op == types.Or {
+		return rpc.Operator_Or
+	}
+
+	return rpc.Operator_And
+}
+
+func operatorFromRPC(op rpc.Operator) types.Operator {
+	if op == rpc.Operator_Or {
+		return types.Or
+	}
+
+	return types.And
+}
+
 func ruleTypeToRpc(ruleType types.RuleType) rpc.RuleType {
 	var rt rpc.RuleType
 	switch ruleType{
@@ -39,6 +103,10 @@ func ruleTypeToRpc(ruleType types.RuleType) rpc.RuleType {
 		rt = rpc.RuleType_ClusterName
 	case types.All:
 		rt = rpc.RuleType_All
+	case types.Compound:
+		rt = rpc.RuleType_Compound
+	case types.Labels:
+		rt = rpc.RuleType_Labels
 	}
 
 	return rt
@@ -69,6 +137,10 @@ func ruleTypeFromRPC(rt rpc.RuleType) types.RuleType {
 		ruleType = types.SourceIP
 	case rpc.RuleType_SharedSecret:
 		ruleType = types.SharedSecret
+	case rpc.RuleType_Compound:
+		ruleType = types.Compound
+	case rpc.RuleType_Labels:
+		ruleType = types.Labels
 	}
 
 	return ruleType
@@ -88,15 +160,74 @@ func ruleActionFromRPC(ra rpc.RuleAction) types.RuleAction {
 	return ruleAction
 }
 
-func ruleToRPC(r *rpc.Rule) types.Rule {
+// ruleToRPC converts a rule received over rpc. the error is an InvalidArgument
+// status if its times can't be parsed.
+func ruleToRPC(r *rpc.Rule) (types.Rule, error) {
+	errs := ruleErrors{}
 	rule := types.Rule{
-		Type:     ruleTypeFromRPC(r.Type),
-		Action:   ruleActionFromRPC(r.Action),
-		Priority: r.Priority,
-		Regex:    r.Regex,
+		ID:              r.ID,
+		ResourceVersion: r.ResourceVersion,
+
+		Type:      ruleTypeFromRPC(r.Type),
+		Action:    ruleActionFromRPC(r.Action),
+		Priority:  r.Priority,
+		Regex:     r.Regex,
+		SecretIDs: r.SecretIDs,
+		CIDRs:     r.CIDRs,
+		Selector:  r.Selector,
+
+		NotBefore: parseRuleTime(r.NotBefore, "notBefore", &errs),
+		NotAfter:  parseRuleTime(r.NotAfter, "notAfter", &errs),
+		TimeZone:  r.TimeZone,
+
+		MaxMatches:    r.MaxMatches,
+		RateLimit:     r.RateLimit,
+		RatePeriod:    r.RatePeriod,
+		HoldOverQuota: r.HoldOverQuota,
+
+		Cluster: clusterSettingsFromRPC(r.Cluster),
+	}
+
+	if rule.Type == types.Compound {
+		rule.Operator = operatorFromRPC(r.Operator)
+	}
+	for _, w := range r.Windows {
+		rule.Windows = append(rule.Windows, types.Window{Schedule: w.Schedule, Duration: w.Duration})
+	}
+	for _, c := range r.Conditions {
+		rule.Conditions = append(rule.Conditions, types.Condition{
+			Type:      ruleTypeFromRPC(c.Type),
+			Regex:     c.Regex,
+			SecretIDs: c.SecretIDs,
+			CIDRs:     c.CIDRs,
+			Selector:  c.Selector,
+			Not:       c.Not,
+		})
+	}
+
+	return rule, errs.err()
+}
+
+func formatRuleTime(t *time.Time) string {
+	if t == nil {
+		return ""
 	}
 
-	return rule
+	return t.Format(time.RFC3339)
+}
+
+func parseRuleTime(s string, field string, errs *ruleErrors) *time.Time {
+	if s == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		errs.add(field, "invalid time %q, expected RFC 3339", s)
+		return nil
+	}
+
+	return &t
 }
 
 func statusFromRPC(s rpc.Status) types.Status {
@@ -108,9 +239,11 @@ func statusFromRPC(s rpc.Status) types.Status {
 	case rpc.Status_Denied:
 		return types.StatusDenied
 	case rpc.Status_Held:
-		return types.StatusAccepted
+		return types.StatusHeld
 	case rpc.Status_Accepted:
 		return types.StatusAccepted
+	case rpc.Status_Registered:
+		return types.StatusRegistered
 	case rpc.Status_Unknown:
 		return types.StatusUnknown
 	default:
@@ -132,6 +265,8 @@ func statusToRPC(s types.Status) rpc.Status {
 		return rpc.Status_Held
 	case types.StatusPending:
 		return rpc.Status_Pending
+	case types.StatusRegistered:
+		return rpc.Status_Registered
 	default:
 		return rpc.Status_Unknown
 	}
@@ -142,7 +277,6 @@ func agentFromRPC(req *rpc.Agent) types.Agent {
 	lastContext.UnmarshalText([]byte(req.LastContact))
 	return types.Agent{
 		ID:            req.ID,
-		Secret:        req.Secret,
 		IP:            req.IP,
 		Status:        statusFromRPC(req.Status),
 		ManifestUrl:   req.ManifestUrl,
@@ -151,6 +285,15 @@ func agentFromRPC(req *rpc.Agent) types.Agent {
 		LastContact:   lastContext,
 		ClusterName:   req.ClusterName,
 		UseExisting:   req.UseExisting,
+		Identity:      req.Identity,
+		DecidedBy:     req.DecidedBy,
+		Labels:        req.Labels,
+		RuleID:        req.RuleID,
+		Cluster:       clusterSettingsFromRPC(req.Cluster),
+
+		RancherClusterID:   req.RancherClusterID,
+		RancherClusterName: req.RancherClusterName,
+		AdoptionAllowedBy:  req.AdoptionAllowedBy,
 	}
 }
 
@@ -158,7 +301,6 @@ func agentToRPC(req types.Agent) *rpc.Agent {
 	lastContact, _ := req.LastContact.MarshalText()
 	return &rpc.Agent{
 		ID:            req.ID,
-		Secret:        req.Secret,
 		IP:            req.IP,
 		Status:        statusToRPC(req.Status),
 		ManifestUrl:   req.ManifestUrl,
@@ -167,5 +309,107 @@ func agentToRPC(req types.Agent) *rpc.Agent {
 		LastContact:   string(lastContact),
 		ClusterName:   req.ClusterName,
 		UseExisting:   req.UseExisting,
+		Identity:      req.Identity,
+		DecidedBy:     req.DecidedBy,
+		Labels:        req.Labels,
+		RuleID:        req.RuleID,
+		Cluster:       clusterSettingsToRPC(req.Cluster),
+
+		RancherClusterID:   req.RancherClusterID,
+		RancherClusterName: req.RancherClusterName,
+		AdoptionAllowedBy:  req.AdoptionAllowedBy,
+	}
+}
+
+func clusterSettingsToRPC(c *types.ClusterSettings) *rpc.ClusterSettings {
+	if c == nil {
+		return nil
+	}
+
+	settings := &rpc.ClusterSettings{
+		Description:             c.Description,
+		Labels:                  c.Labels,
+		Annotations:             c.Annotations,
+		EnableClusterAlerting:   c.EnableClusterAlerting,
+		EnableClusterMonitoring: c.EnableClusterMonitoring,
+		EnableNetworkPolicy:     c.EnableNetworkPolicy,
+	}
+	for _, p := range c.Projects {
+		settings.Projects = append(settings.Projects, &rpc.ClusterProject{Name: p.Name, Description: p.Description})
+	}
+	for _, m := range c.Members {
+		settings.Members = append(settings.Members, &rpc.ClusterMember{
+			UserID:           m.UserID,
+			UserPrincipalID:  m.UserPrincipalID,
+			GroupPrincipalID: m.GroupPrincipalID,
+			Role:             m.Role,
+		})
+	}
+
+	return settings
+}
+
+func clusterSettingsFromRPC(c *rpc.ClusterSettings) *types.ClusterSettings {
+	if c == nil {
+		return nil
+	}
+
+	settings := &types.ClusterSettings{
+		Description:             c.Description,
+		Labels:                  c.Labels,
+		Annotations:             c.Annotations,
+		EnableClusterAlerting:   c.EnableClusterAlerting,
+		EnableClusterMonitoring: c.EnableClusterMonitoring,
+		EnableNetworkPolicy:     c.EnableNetworkPolicy,
+	}
+	for _, p := range c.Projects {
+		settings.Projects = append(settings.Projects, types.ClusterProject{Name: p.Name, Description: p.Description})
+	}
+	for _, m := range c.Members {
+		settings.Members = append(settings.Members, types.ClusterMember{
+			UserID:           m.UserID,
+			UserPrincipalID:  m.UserPrincipalID,
+			GroupPrincipalID: m.GroupPrincipalID,
+			Role:             m.Role,
+		})
+	}
+
+	return settings
+}
+
+func roleFromRPC(r rpc.Role) types.Role {
+	switch r {
+	case rpc.Role_AdminRole:
+		return types.RoleAdmin
+	default:
+		return types.RoleAgent
+	}
+}
+
+func roleToRPC(r types.Role) rpc.Role {
+	switch r {
+	case types.RoleAdmin:
+		return rpc.Role_AdminRole
+	default:
+		return rpc.Role_AgentRole
+	}
+}
+
+func tokenToRPC(t types.Token) *rpc.Token {
+	created, _ := t.Created.MarshalText()
+	return &rpc.Token{
+		ID:          t.ID,
+		Role:        roleToRPC(t.Role),
+		Description: t.Description,
+		Created:     string(created),
+	}
+}
+
+func secretToRPC(s types.Secret) *rpc.Secret {
+	created, _ := s.Created.MarshalText()
+	return &rpc.Secret{
+		ID:          s.ID,
+		Description: s.Description,
+		Created:     string(created),
 	}
 }
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/rulestore"
+	"github.com/ebauman/moo/pkg/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ruleErrors collects the problems with a rule submitted over rpc
+type ruleErrors []*errdetails.BadRequest_FieldViolation
+
+func (e *ruleErrors) add(field string, format string, args ...interface{}) {
+	*e = append(*e, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// err is an InvalidArgument status listing every violation, with a BadRequest
+// detail so clients can pick out the fields
+func (e ruleErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(e))
+	for i, v := range e {
+		messages[i] = fmt.Sprintf("%s: %s", v.Field, v.Description)
+	}
+
+	st := status.New(codes.InvalidArgument, "invalid rule: "+strings.Join(messages, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: e}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// validateRule checks a rule submitted over rpc, normalizing it in place.
+// the checks rules from any backend have to pass are rulestore.CheckRule's,
+// the rest are only made here.
+func validateRule(r *types.Rule) error {
+	errs := ruleErrors{}
+	for _, e := range rulestore.CheckRule(*r) {
+		errs.add(e.Field, "%s", e.Description)
+	}
+
+	switch r.Type {
+	case types.Compound:
+		if r.Operator == "" {
+			r.Operator = types.And
+		}
+		for i := range r.Conditions {
+			parseCondition(&r.Conditions[i], fmt.Sprintf("conditions[%d].", i), &errs)
+		}
+	case types.SourceIP, types.SharedSecret, types.ClusterName, types.All, types.Labels:
+		c := rulestore.RuleConditions(*r)[0]
+		parseCondition(&c, "", &errs)
+		r.CIDRs = c.CIDRs
+	}
+
+	if r.NotBefore != nil && r.NotAfter != nil && !r.NotBefore.Before(*r.NotAfter) {
+		errs.add("notAfter", "must be after notBefore")
+	}
+	if _, err := time.LoadLocation(r.TimeZone); err != nil {
+		errs.add("timeZone", "unknown time zone %q", r.TimeZone)
+	}
+	for i, w := range r.Windows {
+		if _, err := rulestore.ParseWindow(w); err != nil {
+			errs.add(fmt.Sprintf("windows[%d]", i), "%v", err)
+		}
+	}
+
+	if r.Cluster != nil {
+		if r.Action != types.Accept {
+			errs.add("cluster", "cluster settings are only used by %s rules", types.Accept)
+		}
+		for k, v := range r.Cluster.Labels {
+			if msgs := append(validation.IsQualifiedName(k), validation.IsValidLabelValue(v)...); len(msgs) > 0 {
+				errs.add("cluster.labels", "invalid label %s=%s: %s", k, v, strings.Join(msgs, "; "))
+			}
+		}
+		for k := range r.Cluster.Annotations {
+			if msgs := validation.IsQualifiedName(k); len(msgs) > 0 {
+				errs.add("cluster.annotations", "invalid annotation %s: %s", k, strings.Join(msgs, "; "))
+			}
+		}
+
+		projects := map[string]bool{}
+		for i, p := range r.Cluster.Projects {
+			field := fmt.Sprintf("cluster.projects[%d]", i)
+			if p.Name == "" {
+				errs.add(field+".name", "a project needs a name")
+			} else if projects[p.Name] {
+				errs.add(field+".name", "duplicate project %s", p.Name)
+			}
+			projects[p.Name] = true
+		}
+
+		for i, m := range r.Cluster.Members {
+			field := fmt.Sprintf("cluster.members[%d]", i)
+			ids := 0
+			for _, id := range []string{m.UserID, m.UserPrincipalID, m.GroupPrincipalID} {
+				if id != "" {
+					ids++
+				}
+			}
+			if ids != 1 {
+				errs.add(field, "a member needs exactly one of a user id, user principal id or group principal id")
+			}
+			if m.Role == "" {
+				errs.add(field+".role", "a member needs a role")
+			}
+		}
+	}
+
+	if r.MaxMatches < 0 {
+		errs.add("maxMatches", "must not be negative")
+	}
+	if r.RateLimit < 0 {
+		errs.add("rateLimit", "must not be negative")
+	}
+	if r.RateLimit > 0 {
+		if period, err := time.ParseDuration(r.RatePeriod); err != nil || period < time.Second {
+			errs.add("ratePeriod", "invalid period %q, expected a duration of at least 1s", r.RatePeriod)
+		}
+	} else if r.RatePeriod != "" {
+		errs.add("ratePeriod", "a rate period needs a rate limit")
+	}
+
+	return errs.err()
+}
+
+// parseCondition reports the regex, selector and cidr blocks of a condition
+// that don't parse, and normalizes the cidr blocks in place
+func parseCondition(c *types.Condition, prefix string, errs *ruleErrors) {
+	if c.Regex != "" && (c.Type == types.ClusterName || c.Type == types.SourceIP) {
+		if _, err := regexp.Compile(c.Regex); err != nil {
+			errs.add(prefix+"regex", "%v", err)
+		}
+	}
+
+	if c.Selector != "" && c.Type == types.Labels {
+		if _, err := labels.Parse(c.Selector); err != nil {
+			errs.add(prefix+"selector", "%v", err)
+		}
+	}
+
+	if len(c.CIDRs) > 0 && c.Type == types.SourceIP && c.Regex == "" {
+		if cidrs, err := normalizeCIDRs(c.CIDRs); err != nil {
+			errs.add(prefix+"cidrs", "%v", err)
+		} else {
+			c.CIDRs = cidrs
+		}
+	}
+}
+
+// validateLabels checks the labels an agent reports are valid kubernetes
+// labels, so selectors can match them
+func validateLabels(l map[string]string) error {
+	for k, v := range l {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid label key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid value for label %s: %s", k, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
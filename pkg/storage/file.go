@@ -0,0 +1,390 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	opPutAgent     = "putAgent"
+	opDeleteAgent  = "deleteAgent"
+	opPutRules     = "putRules"
+	opDeleteRule   = "deleteRule"
+	opPutToken     = "putToken"
+	opDeleteToken  = "deleteToken"
+	opPutSecret    = "putSecret"
+	opDeleteSecret = "deleteSecret"
+
+	// the journal is rewritten once more entries were appended than this, or
+	// than twice the entries of the last snapshot, whichever is more
+	compactThreshold = 1024
+)
+
+type journalEntry struct {
+	Op     string        `json:"op"`
+	Agent  *types.Agent  `json:"agent,omitempty"`
+	ID     string        `json:"id,omitempty"`
+	Rules  []types.Rule  `json:"rules,omitempty"`
+	Token  *types.Token  `json:"token,omitempty"`
+	Secret *types.Secret `json:"secret,omitempty"`
+}
+
+// FileBackend is a single-file journal. every write is appended as one json line
+// and synced before returning. on open the journal is replayed into memory,
+// a torn trailing line (from a crash mid-write) is discarded, and the file is
+// compacted down to a snapshot of the current state.
+type FileBackend struct {
+	*MemoryBackend
+
+	path string
+	file *os.File
+	log  *log.Logger
+
+	snapshot int // entries written by the last compaction
+	appended int // entries appended since
+}
+
+func NewFileBackend(path string, log *log.Logger) (*FileBackend, error) {
+	f := &FileBackend{
+		MemoryBackend: NewMemoryBackend(),
+		path:          path,
+		log:           log,
+	}
+
+	if err := f.replay(); err != nil {
+		return nil, err
+	}
+
+	if err := f.compact(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *FileBackend) replay() error {
+	file, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening storage file %s: %v", f.path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// anything without a trailing newline is a partial write, drop it
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading storage file %s: %v", f.path, err)
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := journalEntry{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("corrupt entry in storage file %s: %v", f.path, err)
+		}
+
+		f.apply(entry)
+	}
+}
+
+func (f *FileBackend) apply(entry journalEntry) {
+	switch entry.Op {
+	case opPutAgent:
+		if entry.Agent != nil {
+			f.MemoryBackend.PutAgent(*entry.Agent)
+		}
+	case opDeleteAgent:
+		f.MemoryBackend.DeleteAgent(entry.ID)
+	case opPutRules:
+		f.MemoryBackend.PutRules(entry.Rules)
+	case opDeleteRule:
+		f.MemoryBackend.DeleteRule(entry.ID)
+	case opPutToken:
+		if entry.Token != nil {
+			f.MemoryBackend.PutToken(*entry.Token)
+		}
+	case opDeleteToken:
+		f.MemoryBackend.DeleteToken(entry.ID)
+	case opPutSecret:
+		if entry.Secret != nil {
+			f.MemoryBackend.PutSecret(*entry.Secret)
+		}
+	case opDeleteSecret:
+		f.MemoryBackend.DeleteSecret(entry.ID)
+	}
+}
+
+// compact writes the current state to a temporary file and atomically
+// renames it over the journal. callers must hold the write lock.
+func (f *FileBackend) compact() error {
+	tmpPath := f.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating storage file %s: %v", tmpPath, err)
+	}
+
+	w := bufio.NewWriter(tmp)
+	entries := 0
+	for _, a := range f.agents {
+		a := a
+		if err := writeEntry(w, journalEntry{Op: opPutAgent, Agent: &a}); err != nil {
+			tmp.Close()
+			return err
+		}
+		entries++
+	}
+	if err := writeEntry(w, journalEntry{Op: opPutRules, Rules: f.rules}); err != nil {
+		tmp.Close()
+		return err
+	}
+	entries++
+	for _, t := range f.tokens {
+		t := t
+		if err := writeEntry(w, journalEntry{Op: opPutToken, Token: &t}); err != nil {
+			tmp.Close()
+			return err
+		}
+		entries++
+	}
+	for _, s := range f.secrets {
+		s := s
+		if err := writeEntry(w, journalEntry{Op: opPutSecret, Secret: &s}); err != nil {
+			tmp.Close()
+			return err
+		}
+		entries++
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error replacing storage file %s: %v", f.path, err)
+	}
+	syncDir(filepath.Dir(f.path))
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening storage file %s: %v", f.path, err)
+	}
+
+	if f.file != nil {
+		f.file.Close()
+	}
+	f.file = file
+	f.snapshot = entries
+	f.appended = 0
+
+	return nil
+}
+
+func (f *FileBackend) append(entry journalEntry) error {
+	if f.file == nil {
+		return fmt.Errorf("storage file %s is closed", f.path)
+	}
+
+	offset, err := f.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("error seeking storage file %s: %v", f.path, err)
+	}
+
+	w := bufio.NewWriter(f.file)
+	if err := writeEntry(w, entry); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		// don't leave a torn line in front of the next entry
+		f.file.Truncate(offset)
+		return fmt.Errorf("error writing storage file %s: %v", f.path, err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("error syncing storage file %s: %v", f.path, err)
+	}
+
+	f.appended++
+	return nil
+}
+
+// maybeCompact rewrites the journal once enough has been appended to it. the
+// threshold grows with the snapshot so large stores aren't rewritten on every
+// write. a failed compaction is not fatal, the existing journal is still intact.
+func (f *FileBackend) maybeCompact() {
+	threshold := compactThreshold
+	if 2*f.snapshot > threshold {
+		threshold = 2 * f.snapshot
+	}
+
+	if f.appended > threshold {
+		if err := f.compact(); err != nil {
+			f.log.Errorf("error compacting storage file %s: %v", f.path, err)
+		}
+	}
+}
+
+// write operations hold the embedded memory backend's lock for the duration
+// so the journal order always matches the in-memory order. like the memory
+// backend they store deep copies.
+
+func (f *FileBackend) PutAgent(a types.Agent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opPutAgent, Agent: &a}); err != nil {
+		return err
+	}
+
+	f.agents[a.ID] = a.DeepCopy()
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) DeleteAgent(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opDeleteAgent, ID: id}); err != nil {
+		return err
+	}
+
+	delete(f.agents, id)
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) PutRules(rules []types.Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opPutRules, Rules: rules}); err != nil {
+		return err
+	}
+
+	f.rules = copyRules(rules)
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) DeleteRule(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opDeleteRule, ID: id}); err != nil {
+		return err
+	}
+
+	f.deleteRule(id)
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) PutToken(t types.Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opPutToken, Token: &t}); err != nil {
+		return err
+	}
+
+	f.tokens[t.ID] = t
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) DeleteToken(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opDeleteToken, ID: id}); err != nil {
+		return err
+	}
+
+	delete(f.tokens, id)
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) PutSecret(s types.Secret) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opPutSecret, Secret: &s}); err != nil {
+		return err
+	}
+
+	f.secrets[s.ID] = s
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) DeleteSecret(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(journalEntry{Op: opDeleteSecret, ID: id}); err != nil {
+		return err
+	}
+
+	delete(f.secrets, id)
+	f.maybeCompact()
+	return nil
+}
+
+func (f *FileBackend) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func writeEntry(w *bufio.Writer, entry journalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding storage entry: %v", err)
+	}
+
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+
+	d.Sync()
+}
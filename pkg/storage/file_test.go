@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func testLogger() *log.Logger {
+	logger := log.New()
+	logger.SetLevel(log.PanicLevel)
+
+	return logger
+}
+
+func newTestFileBackend(t *testing.T, path string) *FileBackend {
+	f, err := NewFileBackend(path, testLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func tempPath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "moo-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "moo.db")
+}
+
+func countLines(t *testing.T, path string) int {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestFileReplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		journal string
+		agents  []string
+		rules   []string
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			journal: "",
+		},
+		{
+			name:    "puts and deletes",
+			journal: `{"op":"putAgent","agent":{"id":"a"}}` + "\n" + `{"op":"putAgent","agent":{"id":"b"}}` + "\n" + `{"op":"deleteAgent","id":"a"}` + "\n",
+			agents:  []string{"b"},
+		},
+		{
+			name:    "rule deleted",
+			journal: `{"op":"putRules","rules":[{"id":"r1"},{"id":"r2"}]}` + "\n" + `{"op":"deleteRule","id":"r1"}` + "\n",
+			rules:   []string{"r2"},
+		},
+		{
+			name:    "torn trailing line",
+			journal: `{"op":"putAgent","agent":{"id":"a"}}` + "\n" + `{"op":"putAgent","agent":{"id":"b"`,
+			agents:  []string{"a"},
+		},
+		{
+			name:    "blank lines",
+			journal: "\n" + `{"op":"putAgent","agent":{"id":"a"}}` + "\n\n",
+			agents:  []string{"a"},
+		},
+		{
+			name:    "corrupt line",
+			journal: `{"op":"putAgent","agent":{"id":"a"}}` + "\n" + `garbage` + "\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tempPath(t)
+			if err := ioutil.WriteFile(path, []byte(tt.journal), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFileBackend(path, testLogger())
+			if tt.wantErr {
+				if err == nil {
+					f.Close()
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			if got := len(f.ListAgents()); got != len(tt.agents) {
+				t.Fatalf("got %d agents, want %d", got, len(tt.agents))
+			}
+			for _, id := range tt.agents {
+				if _, ok := f.GetAgent(id); !ok {
+					t.Errorf("agent %s missing", id)
+				}
+			}
+			rules := f.ListRules()
+			if len(rules) != len(tt.rules) {
+				t.Fatalf("got %d rules, want %d", len(rules), len(tt.rules))
+			}
+			for i, id := range tt.rules {
+				if rules[i].ID != id {
+					t.Errorf("got rule %s, want %s", rules[i].ID, id)
+				}
+			}
+
+			// opening compacts the journal, the torn line must be gone
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				if !bytes.HasSuffix(scanner.Bytes(), []byte("}")) {
+					t.Errorf("unexpected line after compaction: %s", scanner.Text())
+				}
+			}
+		})
+	}
+}
+
+func TestFileCompaction(t *testing.T) {
+	path := tempPath(t)
+	f := newTestFileBackend(t, path)
+
+	// a snapshot larger than the threshold
+	const agents = 2 * compactThreshold
+	for i := 0; i < agents; i++ {
+		if err := f.PutAgent(types.Agent{ID: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.Close()
+
+	f = newTestFileBackend(t, path)
+	defer f.Close()
+	if f.snapshot != agents+1 {
+		t.Fatalf("snapshot has %d entries, want %d", f.snapshot, agents+1)
+	}
+
+	// updating an agent appends, it doesn't rewrite the file
+	if err := f.PutAgent(types.Agent{ID: "0", ClusterName: "edge"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := countLines(t, path); got != agents+2 {
+		t.Fatalf("journal has %d lines after one write, want %d", got, agents+2)
+	}
+
+	// until twice the snapshot has been appended
+	for i := 0; i < 2*f.snapshot; i++ {
+		if err := f.PutAgent(types.Agent{ID: "0", ClusterName: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := countLines(t, path); got > agents+2 {
+		t.Fatalf("journal has %d lines, expected it to be compacted", got)
+	}
+	if f.appended != 0 {
+		t.Fatalf("%d entries appended after compaction, want 0", f.appended)
+	}
+
+	f.Close()
+	f = newTestFileBackend(t, path)
+	defer f.Close()
+	a, ok := f.GetAgent("0")
+	if !ok || a.ClusterName != strconv.Itoa(2*(agents+1)-1) {
+		t.Fatalf("got agent %+v after reopening", a)
+	}
+	if got := len(f.ListAgents()); got != agents {
+		t.Fatalf("got %d agents after reopening, want %d", got, agents)
+	}
+}
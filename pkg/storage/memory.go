@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"github.com/ebauman/moo/pkg/types"
+	"sync"
+)
+
+// MemoryBackend keeps everything in process memory, nothing survives a restart.
+// agents and rules are copied on the way in and out, so neither side can
+// change what is stored through a map or slice it holds on to.
+type MemoryBackend struct {
+	mu      sync.RWMutex
+	agents  map[string]types.Agent
+	rules   []types.Rule
+	tokens  map[string]types.Token
+	secrets map[string]types.Secret
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		agents:  make(map[string]types.Agent, 0),
+		rules:   make([]types.Rule, 0),
+		tokens:  make(map[string]types.Token, 0),
+		secrets: make(map[string]types.Secret, 0),
+	}
+}
+
+func (m *MemoryBackend) GetAgent(id string) (types.Agent, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a, ok := m.agents[id]
+	return a.DeepCopy(), ok
+}
+
+func (m *MemoryBackend) ListAgents() []types.Agent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	agents := make([]types.Agent, 0, len(m.agents))
+	for _, v := range m.agents {
+		agents = append(agents, v.DeepCopy())
+	}
+
+	return agents
+}
+
+func (m *MemoryBackend) PutAgent(a types.Agent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.agents[a.ID] = a.DeepCopy()
+	return nil
+}
+
+func (m *MemoryBackend) DeleteAgent(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.agents, id)
+	return nil
+}
+
+func (m *MemoryBackend) ListRules() []types.Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return copyRules(m.rules)
+}
+
+func (m *MemoryBackend) PutRules(rules []types.Rule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rules = copyRules(rules)
+
+	return nil
+}
+
+func (m *MemoryBackend) DeleteRule(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deleteRule(id)
+	return nil
+}
+
+func (m *MemoryBackend) deleteRule(id string) {
+	for i, r := range m.rules {
+		if r.ID == id {
+			m.rules = append(m.rules[:i:i], m.rules[i+1:]...)
+			return
+		}
+	}
+}
+
+func copyRules(rules []types.Rule) []types.Rule {
+	out := make([]types.Rule, len(rules))
+	for i, r := range rules {
+		out[i] = r.DeepCopy()
+	}
+
+	return out
+}
+
+func (m *MemoryBackend) GetToken(id string) (types.Token, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.tokens[id]
+	return t, ok
+}
+
+func (m *MemoryBackend) ListTokens() []types.Token {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tokens := make([]types.Token, 0, len(m.tokens))
+	for _, v := range m.tokens {
+		tokens = append(tokens, v)
+	}
+
+	return tokens
+}
+
+func (m *MemoryBackend) PutToken(t types.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[t.ID] = t
+	return nil
+}
+
+func (m *MemoryBackend) DeleteToken(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tokens, id)
+	return nil
+}
+
+func (m *MemoryBackend) GetSecret(id string) (types.Secret, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.secrets[id]
+	return s, ok
+}
+
+func (m *MemoryBackend) ListSecrets() []types.Secret {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	secrets := make([]types.Secret, 0, len(m.secrets))
+	for _, v := range m.secrets {
+		secrets = append(secrets, v)
+	}
+
+	return secrets
+}
+
+func (m *MemoryBackend) PutSecret(s types.Secret) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.secrets[s.ID] = s
+	return nil
+}
+
+func (m *MemoryBackend) DeleteSecret(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.secrets, id)
+	return nil
+}
+
+func (m *MemoryBackend) Close() error {
+	return nil
+}
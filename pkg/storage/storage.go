@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/ebauman/moo/pkg/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	Memory     = "memory"
+	File       = "file"
+	Kubernetes = "kubernetes" // built by kubernetes.NewStorageBackend
+)
+
+// Backend persists agents and rules on behalf of agentstore.Store and rulestore.Store.
+// Implementations must be safe for concurrent use, and must not keep or hand
+// out maps, slices or pointers shared with their callers.
+type Backend interface {
+	GetAgent(id string) (types.Agent, bool)
+	ListAgents() []types.Agent
+	PutAgent(a types.Agent) error
+	DeleteAgent(id string) error
+
+	// rules are listed in evaluation order. PutRules creates or updates the
+	// given rules, a rule is only ever removed by DeleteRule.
+	ListRules() []types.Rule
+	PutRules(rules []types.Rule) error
+	DeleteRule(id string) error
+
+	GetToken(id string) (types.Token, bool)
+	ListTokens() []types.Token
+	PutToken(t types.Token) error
+	DeleteToken(id string) error
+
+	GetSecret(id string) (types.Secret, bool)
+	ListSecrets() []types.Secret
+	PutSecret(s types.Secret) error
+	DeleteSecret(id string) error
+
+	Close() error
+}
+
+// NewBackend builds one of the built-in backends by name
+func NewBackend(kind string, path string, log *log.Logger) (Backend, error) {
+	switch kind {
+	case Memory, "":
+		return NewMemoryBackend(), nil
+	case File:
+		if path == "" {
+			return nil, fmt.Errorf("storage path required for %s storage", File)
+		}
+		return NewFileBackend(path, log)
+	}
+
+	return nil, fmt.Errorf("unknown storage type %s", kind)
+}
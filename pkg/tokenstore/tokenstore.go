@@ -0,0 +1,104 @@
+package tokenstore
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/ebauman/moo/pkg/keys"
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/ebauman/moo/pkg/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	idLength     = 6
+	secretLength = 16
+)
+
+// Store keeps bearer tokens of the form <id>.<secret>. the id is used to look
+// a token up, the secret is only kept as a sha256 hash.
+type Store struct {
+	mu      sync.RWMutex
+	backend storage.Backend
+}
+
+func NewStore(backend storage.Backend) *Store {
+	return &Store{
+		backend: backend,
+	}
+}
+
+// CreateToken mints a new token and returns it along with the full bearer
+// value, which cannot be recovered later
+func (s *Store) CreateToken(role types.Role, description string) (types.Token, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := keys.Random(idLength)
+	if err != nil {
+		return types.Token{}, "", err
+	}
+	secret, err := keys.Random(secretLength)
+	if err != nil {
+		return types.Token{}, "", err
+	}
+
+	t := types.Token{
+		ID:          id,
+		Hash:        keys.Hash(secret),
+		Role:        role,
+		Description: description,
+		Created:     time.Now(),
+	}
+
+	if err := s.backend.PutToken(t); err != nil {
+		return types.Token{}, "", err
+	}
+
+	return t, fmt.Sprintf("%s.%s", id, secret), nil
+}
+
+// Authenticate returns the token matching a bearer value, or nil
+func (s *Store) Authenticate(bearer string) *types.Token {
+	parts := strings.SplitN(bearer, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.backend.GetToken(parts[0])
+	if !ok {
+		return nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(keys.Hash(parts[1]))) != 1 {
+		return nil
+	}
+
+	return &t
+}
+
+func (s *Store) ListTokens() []types.Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.backend.ListTokens()
+}
+
+func (s *Store) DeleteToken(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.backend.GetToken(id); !ok {
+		return false, nil
+	}
+
+	if err := s.backend.DeleteToken(id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
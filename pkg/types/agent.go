@@ -9,20 +9,50 @@ var (
 	StatusDenied   Status = "denied"
 	StatusPending  Status = "pending"
 	StatusError    Status = "error"
+	// accepted and the cluster is active in rancher
+	StatusRegistered Status = "registered"
 )
 
 type Agent struct {
 	ID            string    `json:"id"`
-	Secret        string    `json:"secret"`
+	SecretHash    string    `json:"secretHash"` // sha256 of the shared secret, never the secret itself
 	IP            string    `json:"ip"`
 	Status        Status    `json:"status"`
 	ManifestUrl   string    `json:"manifestUrl"`
 	StatusMessage string    `json:"statusMessage"`
 	Completed     bool      `json:"completed"`
 	LastContact   time.Time `json:"time"`
+	PendingSince  time.Time `json:"pendingSince"` // when the agent last became pending
 
-	ClusterName string
-	UseExisting bool
+	ClusterName string `json:"clusterName"`
+	UseExisting bool   `json:"useExisting"`
+
+	// labels reported by the agent, set on the command line or collected from its cluster
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// identity from the verified client certificate the agent registered with
+	Identity string `json:"identity,omitempty"`
+
+	// the admin who last set the status by hand. cleared when the rules decide.
+	DecidedBy string `json:"decidedBy,omitempty"`
+
+	// the admin who allowed the agent to adopt an existing rancher cluster it
+	// can't prove is bound to it. cleared whenever the status changes again.
+	AdoptionAllowedBy string `json:"adoptionAllowedBy,omitempty"`
+
+	// the rule that last decided the agent, and the cluster settings it carried
+	// at the time. empty when the default action applied.
+	RuleID  string           `json:"ruleID,omitempty"`
+	Cluster *ClusterSettings `json:"cluster,omitempty"`
+
+	// the rancher cluster created or adopted for the agent. its name comes
+	// from the cluster name template and may differ from ClusterName.
+	RancherClusterID   string `json:"rancherClusterID,omitempty"`
+	RancherClusterName string `json:"rancherClusterName,omitempty"`
+
+	// why creating the projects and members of the rancher cluster last
+	// failed. retried until it succeeds.
+	ClusterConfigError string `json:"clusterConfigError,omitempty"`
 }
 
 type Status string
@@ -0,0 +1,91 @@
+package types
+
+import "time"
+
+// DeepCopy returns a copy of the agent that shares no maps, slices or
+// pointers with it
+func (a Agent) DeepCopy() Agent {
+	a.Labels = copyMap(a.Labels)
+	a.Cluster = a.Cluster.DeepCopy()
+
+	return a
+}
+
+// DeepCopy returns a copy of the rule that shares no maps, slices or
+// pointers with it
+func (r Rule) DeepCopy() Rule {
+	r.SecretIDs = copyStrings(r.SecretIDs)
+	r.CIDRs = copyStrings(r.CIDRs)
+	r.NotBefore = copyTime(r.NotBefore)
+	r.NotAfter = copyTime(r.NotAfter)
+	r.Cluster = r.Cluster.DeepCopy()
+
+	if r.Conditions != nil {
+		conditions := make([]Condition, len(r.Conditions))
+		for i, c := range r.Conditions {
+			c.SecretIDs = copyStrings(c.SecretIDs)
+			c.CIDRs = copyStrings(c.CIDRs)
+			conditions[i] = c
+		}
+		r.Conditions = conditions
+	}
+
+	if r.Windows != nil {
+		r.Windows = append([]Window{}, r.Windows...)
+	}
+
+	if r.Usage.Recent != nil {
+		r.Usage.Recent = append([]time.Time{}, r.Usage.Recent...)
+	}
+
+	return r
+}
+
+// DeepCopy returns a copy of the settings, nil if they are nil
+func (c *ClusterSettings) DeepCopy() *ClusterSettings {
+	if c == nil {
+		return nil
+	}
+
+	out := *c
+	out.Labels = copyMap(c.Labels)
+	out.Annotations = copyMap(c.Annotations)
+	if c.Projects != nil {
+		out.Projects = append([]ClusterProject{}, c.Projects...)
+	}
+	if c.Members != nil {
+		out.Members = append([]ClusterMember{}, c.Members...)
+	}
+
+	return &out
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+
+	return append([]string{}, s...)
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	out := *t
+	return &out
+}
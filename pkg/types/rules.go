@@ -1,22 +1,132 @@
 package types
 
+import "time"
+
 const (
 	SourceIP RuleType = "SourceIP"
 	SharedSecret RuleType = "SharedSecret"
 	ClusterName RuleType = "ClusterName"
 	All RuleType = "All"
+	Compound RuleType = "Compound"
+	Labels RuleType = "Labels"
 
 	Hold RuleAction = "Hold"
 	Accept RuleAction = "Accept"
 	Deny RuleAction = "Deny"
+
+	And Operator = "And"
+	Or  Operator = "Or"
 )
 
 type RuleType string
 type RuleAction string
+type Operator string
 
 type Rule struct {
-	Type RuleType
-	Action RuleAction
-	Priority int32
-	Regex string
+	ID              string `json:"id"`
+	ResourceVersion int64  `json:"resourceVersion"` // bumped on every update
+
+	// rules of equal priority are evaluated oldest first
+	Created time.Time `json:"created"`
+
+	Type     RuleType   `json:"type"`
+	Action   RuleAction `json:"action"`
+	Priority int32      `json:"priority"`
+	Regex    string     `json:"regex"`
+
+	// for SharedSecret rules, the ids of the secrets that match. empty matches any stored secret.
+	SecretIDs []string `json:"secretIDs,omitempty"`
+
+	// for SourceIP rules, the cidr blocks that match. when set the regex is not used.
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	// for Labels rules, a kubernetes label selector matched against the agent's labels
+	Selector string `json:"selector,omitempty"`
+
+	// for Compound rules, the conditions to evaluate and how their results are
+	// combined. the Regex, SecretIDs, CIDRs and Selector of the rule itself are
+	// not used.
+	Operator   Operator    `json:"operator,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// when set the rule is only active from NotBefore, and until NotAfter
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+
+	// when set the rule is only active while one of the windows is open.
+	// schedules are read in TimeZone, which defaults to UTC.
+	Windows  []Window `json:"windows,omitempty"`
+	TimeZone string   `json:"timeZone,omitempty"`
+
+	// limits on how many agents the rule may decide, in total and per
+	// RatePeriod. zero is unlimited. once a limit is reached the rule stops
+	// matching, or holds the agents it matches if HoldOverQuota is set.
+	MaxMatches    int64  `json:"maxMatches,omitempty"`
+	RateLimit     int64  `json:"rateLimit,omitempty"`
+	RatePeriod    string `json:"ratePeriod,omitempty"`
+	HoldOverQuota bool   `json:"holdOverQuota,omitempty"`
+
+	// for Accept rules, applied to the rancher clusters created for the agents
+	// the rule accepts
+	Cluster *ClusterSettings `json:"cluster,omitempty"`
+
+	// kept by the server, not changed by updates
+	Usage RuleUsage `json:"usage"`
+}
+
+// ClusterSettings configure a cluster moo creates in rancher. they are not
+// applied to existing clusters an agent is allowed to use.
+type ClusterSettings struct {
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	EnableClusterAlerting   bool `json:"enableClusterAlerting,omitempty"`
+	EnableClusterMonitoring bool `json:"enableClusterMonitoring,omitempty"`
+	EnableNetworkPolicy     bool `json:"enableNetworkPolicy,omitempty"`
+
+	// created once the cluster has been created
+	Projects []ClusterProject `json:"projects,omitempty"`
+	Members  []ClusterMember  `json:"members,omitempty"`
+}
+
+type ClusterProject struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ClusterMember is granted a cluster role, e.g. cluster-owner or
+// cluster-member. exactly one of the ids is set.
+type ClusterMember struct {
+	UserID           string `json:"userID,omitempty"`           // rancher user, e.g. u-abcde
+	UserPrincipalID  string `json:"userPrincipalID,omitempty"`  // e.g. github_user://1234
+	GroupPrincipalID string `json:"groupPrincipalID,omitempty"` // e.g. github_team://5678
+	Role             string `json:"role"`                       // role template id
+}
+
+// RuleUsage counts the agents a rule has decided
+type RuleUsage struct {
+	Matches int64 `json:"matches"`
+
+	// when the rule last decided agents, at most RateLimit of them, for
+	// enforcing the rate limit
+	Recent []time.Time `json:"recent,omitempty"`
+}
+
+// Window opens at each time matching Schedule, a five field cron expression
+// (minute hour day-of-month month day-of-week), and stays open for Duration.
+type Window struct {
+	Schedule string `json:"schedule"`
+	Duration string `json:"duration"`
+}
+
+// Condition is a single match within a Compound rule. it matches the same way
+// a rule of its Type would, inverted if Not is set.
+type Condition struct {
+	Type      RuleType `json:"type"`
+	Regex     string   `json:"regex,omitempty"`
+	SecretIDs []string `json:"secretIDs,omitempty"`
+	CIDRs     []string `json:"cidrs,omitempty"`
+	Selector  string   `json:"selector,omitempty"`
+	Not       bool     `json:"not,omitempty"`
 }
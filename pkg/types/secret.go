@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// Secret is a shared secret agents present at registration. like tokens, only
+// a hash is kept and the secret itself is handed out once when it is minted.
+type Secret struct {
+	ID          string    `json:"id"`
+	Hash        string    `json:"hash"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+}
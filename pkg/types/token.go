@@ -0,0 +1,21 @@
+package types
+
+import "time"
+
+const (
+	RoleNone  Role = ""
+	RoleAgent Role = "agent"
+	RoleAdmin Role = "admin"
+)
+
+type Role string
+
+// Token is a bearer token. only a hash of the secret half is ever stored,
+// the full token is handed out once when it is created.
+type Token struct {
+	ID          string    `json:"id"`
+	Hash        string    `json:"hash"`
+	Role        Role      `json:"role"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+}
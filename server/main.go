@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"github.com/ebauman/moo/pkg/config"
+	"github.com/ebauman/moo/pkg/kubernetes"
 	mooLogger "github.com/ebauman/moo/pkg/logger"
 	"github.com/ebauman/moo/pkg/rancher"
 	mooServer "github.com/ebauman/moo/pkg/server"
+	"github.com/ebauman/moo/pkg/storage"
+	"github.com/pires/go-proxyproto"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"io/ioutil"
 	"net"
 	"os"
 	"sync"
@@ -70,6 +77,47 @@ func main() {
 				Value: 600, // 10 minutes
 				EnvVars: []string{"MOO_ERROR_TIME"},
 			},
+			&cli.IntFlag{
+				Name: "resync-time",
+				Usage: "time in seconds between periodic re-evaluations of all agents",
+				Value: 30,
+				EnvVars: []string{"MOO_RESYNC_TIME"},
+			},
+			&cli.StringFlag{
+				Name: "default-action",
+				Usage: "action for agents no rule matches (pending, hold, deny)",
+				Value: "pending",
+				EnvVars: []string{"MOO_DEFAULT_ACTION"},
+			},
+			&cli.IntFlag{
+				Name: "pending-timeout",
+				Usage: "time in seconds after which agents still pending are moved to the pending timeout action, 0 to wait forever",
+				EnvVars: []string{"MOO_PENDING_TIMEOUT"},
+			},
+			&cli.StringFlag{
+				Name: "pending-timeout-action",
+				Usage: "action for agents still pending after the pending timeout (hold, deny)",
+				Value: "deny",
+				EnvVars: []string{"MOO_PENDING_TIMEOUT_ACTION"},
+			},
+			&cli.StringFlag{
+				Name: "cluster-name-template",
+				Usage: "template for the names of rancher clusters, e.g. {{.ClusterName}}-{{.ShortID}} or {{index .Labels \"topology.kubernetes.io/region\"}}-{{.ShortID}}",
+				Value: "{{.ClusterName}}",
+				EnvVars: []string{"MOO_CLUSTER_NAME_TEMPLATE"},
+			},
+			&cli.StringFlag{
+				Name: "name-collision",
+				Usage: "what happens when a rancher cluster name is taken (fail, suffix, adopt)",
+				Value: "fail",
+				EnvVars: []string{"MOO_NAME_COLLISION"},
+			},
+			&cli.IntFlag{
+				Name: "workers",
+				Usage: "number of agents reconciled in parallel",
+				Value: 1,
+				EnvVars: []string{"MOO_WORKERS"},
+			},
 			&cli.StringFlag{
 				Name: "loglevel",
 				Usage: "log level (trace, debug, info, warning, error, fatal, panic)",
@@ -88,6 +136,67 @@ func main() {
 				Usage: "path to key file to secure client communications",
 				EnvVars: []string{"MOO_TLS_KEY"},
 			},
+			&cli.StringFlag{
+				Name: "tls-client-ca",
+				Usage: "path to ca bundle used to verify client certificates. enables client certificate verification",
+				EnvVars: []string{"MOO_TLS_CLIENT_CA"},
+			},
+			&cli.BoolFlag{
+				Name: "require-client-cert",
+				Usage: "reject clients that do not present a certificate signed by --tls-client-ca",
+				Value: false,
+				EnvVars: []string{"MOO_REQUIRE_CLIENT_CERT"},
+			},
+			&cli.StringFlag{
+				Name: "admin-token",
+				Usage: "bootstrap bearer token granting the admin role, use it to create further tokens with mooctl",
+				EnvVars: []string{"MOO_ADMIN_TOKEN"},
+			},
+			&cli.StringSliceFlag{
+				Name: "admin-identity",
+				Usage: "client certificate identity (common name) granted the admin role, may be repeated",
+				EnvVars: []string{"MOO_ADMIN_IDENTITIES"},
+			},
+			&cli.BoolFlag{
+				Name: "require-agent-auth",
+				Usage: "require agents to present an agent token or client certificate",
+				Value: false,
+				EnvVars: []string{"MOO_REQUIRE_AGENT_AUTH"},
+			},
+			&cli.StringSliceFlag{
+				Name: "trusted-proxy",
+				Usage: "ip or cidr of a proxy in front of the server whose x-forwarded-for or proxy protocol header is trusted, may be repeated",
+				EnvVars: []string{"MOO_TRUSTED_PROXIES"},
+			},
+			&cli.BoolFlag{
+				Name: "proxy-protocol",
+				Usage: "accept proxy protocol headers from trusted proxies",
+				Value: false,
+				EnvVars: []string{"MOO_PROXY_PROTOCOL"},
+			},
+			&cli.StringFlag{
+				Name: "storage",
+				Usage: "storage backend for agents and rules (memory, file, kubernetes)",
+				Value: storage.Memory,
+				EnvVars: []string{"MOO_STORAGE"},
+			},
+			&cli.StringFlag{
+				Name: "storage-path",
+				Usage: "path to storage file when using file storage",
+				Value: "moo.db",
+				EnvVars: []string{"MOO_STORAGE_PATH"},
+			},
+			&cli.StringFlag{
+				Name: "storage-namespace",
+				Usage: "namespace for MooAgent and MooRule resources when using kubernetes storage",
+				Value: "moo-system",
+				EnvVars: []string{"MOO_STORAGE_NAMESPACE"},
+			},
+			&cli.StringFlag{
+				Name: "kubeconfig",
+				Usage: "kubeconfig for kubernetes storage if running outside of cluster",
+				EnvVars: []string{"KUBECONFIG"},
+			},
 		},
 	}
 
@@ -119,24 +228,75 @@ func buildConfigFromFlags(ctx *cli.Context) *config.ServerConfig {
 	cfg.HoldTime = int32(ctx.Int("hold-time"))
 	cfg.PendingTime = int32(ctx.Int("pending-time"))
 	cfg.ErrorTime = int32(ctx.Int("error-time"))
+	cfg.ResyncTime = int32(ctx.Int("resync-time"))
+	cfg.Workers = ctx.Int("workers")
+	cfg.DefaultAction = ctx.String("default-action")
+	cfg.PendingTimeout = int32(ctx.Int("pending-timeout"))
+	cfg.PendingTimeoutAction = ctx.String("pending-timeout-action")
+	cfg.ClusterNameTemplate = ctx.String("cluster-name-template")
+	cfg.NameCollision = ctx.String("name-collision")
 	cfg.TLSCert = ctx.String("tls-cert")
 	cfg.TLSKey = ctx.String("tls-key")
+	cfg.TLSClientCA = ctx.String("tls-client-ca")
+	cfg.RequireClientCert = ctx.Bool("require-client-cert")
+	cfg.AdminToken = ctx.String("admin-token")
+	cfg.AdminIdentities = ctx.StringSlice("admin-identity")
+	cfg.RequireAgentAuth = ctx.Bool("require-agent-auth")
+	cfg.TrustedProxies = ctx.StringSlice("trusted-proxy")
+	cfg.ProxyProtocol = ctx.Bool("proxy-protocol")
+	cfg.Storage = ctx.String("storage")
+	cfg.StoragePath = ctx.String("storage-path")
+	cfg.StorageNamespace = ctx.String("storage-namespace")
+	cfg.KubeConfig = ctx.String("kubeconfig")
 
 	return cfg
 }
 
-func loadTLSCredentials(certPath string, keyPath string) (credentials.TransportCredentials, error) {
-	serverCert, err := tls.LoadX509KeyPair(certPath, keyPath)
+func loadTLSCredentials(cfg *config.ServerConfig) (credentials.TransportCredentials, error) {
+	serverCert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := &tls.Config{
+	tlsConfig := &tls.Config{
 		Certificates: []tls.Certificate{serverCert},
 		ClientAuth: tls.NoClientCert,
 	}
 
-	return credentials.NewTLS(cfg), nil
+	if cfg.TLSClientCA != "" {
+		clientCA, err := ioutil.ReadFile(cfg.TLSClientCA)
+		if err != nil {
+			return nil, err
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(clientCA) {
+			return nil, fmt.Errorf("failed to add client ca certificate to cert pool")
+		}
+
+		tlsConfig.ClientCAs = certPool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if cfg.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if cfg.RequireClientCert {
+		return nil, fmt.Errorf("--require-client-cert needs --tls-client-ca")
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func buildBackend(cfg *config.ServerConfig) (storage.Backend, error) {
+	if cfg.Storage != storage.Kubernetes {
+		return storage.NewBackend(cfg.Storage, cfg.StoragePath, logger)
+	}
+
+	k8sClient, err := kubernetes.NewClient(cfg.KubeConfig, logger, context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewStorageBackend(k8sClient, cfg.StorageNamespace)
 }
 
 func run(ctx *cli.Context) error {
@@ -148,20 +308,51 @@ func run(ctx *cli.Context) error {
 		logger.Fatalf("error building rancher server: %v", err)
 	}
 
-	tlsCreds, err := loadTLSCredentials(cfg.TLSCert, cfg.TLSKey)
+	tlsCreds, err := loadTLSCredentials(cfg)
 	if err != nil {
-		logger.Fatalf("error loading tls credentials: ", err)
+		logger.Fatalf("error loading tls credentials: %v", err)
 	}
 
-	rpc := grpc.NewServer(grpc.Creds(tlsCreds))
+	if _, err := mooServer.ParseTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatalf("error parsing trusted proxies: %v", err)
+	}
 
-	server := mooServer.NewServer(cfg, r, logger, rpc)
+	backend, err := buildBackend(cfg)
+	if err != nil {
+		logger.Fatalf("error building storage backend: %v", err)
+	}
+	defer backend.Close()
+
+	server, err := mooServer.NewServer(cfg, r, backend, logger)
+	if err != nil {
+		logger.Fatalf("error building moo server: %v", err)
+	}
+
+	rpc := grpc.NewServer(
+		grpc.Creds(tlsCreds),
+		grpc.UnaryInterceptor(server.AuthorizeUnary),
+		grpc.StreamInterceptor(server.AuthorizeStream),
+	)
+	server.Register(rpc)
+
+	if cfg.AdminToken == "" && len(cfg.AdminIdentities) == 0 {
+		logger.Warnf("no --admin-token or --admin-identity set, admin rpcs are only available to existing admin tokens")
+	}
 
 	lis, err := net.Listen("tcp", ":8080")
 	if err != nil {
 		logger.Fatalf("failed to create net listener: %v", err)
 	}
 
+	if cfg.ProxyProtocol {
+		// headers from anyone but a trusted proxy are read and ignored
+		policy, err := proxyproto.LaxWhiteListPolicy(cfg.TrustedProxies)
+		if err != nil {
+			logger.Fatalf("error parsing trusted proxies: %v", err)
+		}
+		lis = &proxyproto.Listener{Listener: lis, Policy: policy}
+	}
+
 	go rpc.Serve(lis) // is this right?
 
 	var wg sync.WaitGroup